@@ -1,13 +1,28 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/infrastructure/memory"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/infrastructure/metrics"
 	"github.com/AchimGrolimund/CSP-Scout-API/pkg/infrastructure/mongodb"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/infrastructure/postgres"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/infrastructure/sqlite"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/infrastructure/webhook"
 	"github.com/AchimGrolimund/CSP-Scout-API/pkg/interfaces/http/handlers"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/interfaces/http/middleware"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/interfaces/http/server"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -24,37 +39,179 @@ func main() {
 		log.Printf("Warning: .env file not found: %v", err)
 	}
 
-	// MongoDB configuration
-	mongoURI := getEnv("MONGODB_URI", "mongodb://localhost:27017")
-	dbName := getEnv("MONGODB_DATABASE", "csp_scout")
-	collectionName := getEnv("MONGODB_COLLECTION", "reports")
+	// Open the configured storage backend
+	backend := getEnv("STORAGE_BACKEND", "mongodb")
+	repo, err := openRepository(backend)
+	if err != nil {
+		log.Fatalf("Failed to open %s storage: %v", backend, err)
+	}
+
+	// Wait for the storage backend to become reachable before serving traffic
+	attempts, err := strconv.Atoi(getEnv("STARTUP_PING_ATTEMPTS", "10"))
+	if err != nil || attempts < 1 {
+		log.Fatalf("Invalid STARTUP_PING_ATTEMPTS: %q", getEnv("STARTUP_PING_ATTEMPTS", ""))
+	}
+	err = application.WaitForRepository(context.Background(), repo, attempts, time.Second, func(attempt int, err error) {
+		log.Printf("%s storage not reachable (attempt %d/%d): %v", backend, attempt, attempts, err)
+	})
+	if err != nil {
+		log.Fatalf("%s storage not reachable: %v", backend, err)
+	}
+
+	switch r := repo.(type) {
+	case *mongodb.MongoRepository:
+		if err := r.EnsureIndexes(context.Background()); err != nil {
+			log.Printf("Warning: failed to create MongoDB indexes: %v", err)
+		}
+	case *postgres.PostgresRepository:
+		if err := r.Migrate(context.Background()); err != nil {
+			log.Fatalf("Failed to migrate PostgreSQL schema: %v", err)
+		}
+	}
 
-	// Initialize MongoDB repository
-	repo, err := mongodb.NewMongoRepository(mongoURI, dbName, collectionName)
+	// Record repository latencies and ingested reports
+	collector := metrics.New()
+
+	// Classify noise with the built-in rules and any from NOISE_RULES_FILE
+	classifier, err := noiseClassifier(getEnv("NOISE_RULES_FILE", ""))
 	if err != nil {
-		log.Fatalf("Failed to connect to MongoDB: %v", err)
+		log.Fatalf("Invalid NOISE_RULES_FILE: %v", err)
+	}
+
+	// Post issue events such as regressions to ISSUE_WEBHOOK_URL, if set. The
+	// queued events are delivered on shutdown before the repository is closed.
+	var events application.IssueEventHandler
+	closers := server.Closers{repo}
+	if url := getEnv("ISSUE_WEBHOOK_URL", ""); url != "" {
+		timeout, err := time.ParseDuration(getEnv("ISSUE_WEBHOOK_TIMEOUT", "10s"))
+		if err != nil || timeout <= 0 {
+			log.Fatalf("Invalid ISSUE_WEBHOOK_TIMEOUT: %q", getEnv("ISSUE_WEBHOOK_TIMEOUT", ""))
+		}
+		notifier := webhook.NewNotifier(url, timeout)
+		events = notifier
+		closers = server.Closers{notifier, repo}
 	}
 
 	// Create service
-	service := application.NewService(repo)
+	service := application.NewService(metrics.InstrumentRepository(repo, collector), classifier, events)
 
 	// Initialize Gin router
 	router := gin.Default()
 
-	// Add CORS middleware
-	router.Use(cors.Default())
+	// Only honour X-Forwarded-For from the configured proxies when resolving client IPs
+	if err := router.SetTrustedProxies(getEnvList("TRUSTED_PROXIES")); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+
+	// Record request latencies and rejected submissions
+	router.Use(collector.Middleware(handlers.IngestPaths))
+
+	// Only allow cross-origin requests from the configured origins, or from any origin if none are set
+	router.Use(cors.New(corsConfig(getEnvList("CORS_ALLOWED_ORIGINS"))))
+
+	// Throttle report submissions per client IP and cap request bodies
+	var ingest []gin.HandlerFunc
+	rateLimit, err := strconv.ParseFloat(getEnv("RATE_LIMIT_RPS", "10"), 64)
+	if err != nil || rateLimit < 0 {
+		log.Fatalf("Invalid RATE_LIMIT_RPS: %q", getEnv("RATE_LIMIT_RPS", ""))
+	}
+	rateBurst, err := strconv.Atoi(getEnv("RATE_LIMIT_BURST", "20"))
+	if err != nil || rateBurst < 1 {
+		log.Fatalf("Invalid RATE_LIMIT_BURST: %q", getEnv("RATE_LIMIT_BURST", ""))
+	}
+	if rateLimit > 0 {
+		ingest = append(ingest, middleware.RateLimit(middleware.NewRateLimiter(rateLimit, rateBurst)))
+	}
+	maxBodyBytes, err := strconv.ParseInt(getEnv("MAX_BODY_BYTES", "262144"), 10, 64)
+	if err != nil || maxBodyBytes < 0 {
+		log.Fatalf("Invalid MAX_BODY_BYTES: %q", getEnv("MAX_BODY_BYTES", ""))
+	}
+	router.Use(middleware.LimitBody(maxBodyBytes))
+
+	// Expose metrics in the Prometheus text format
+	router.GET("/metrics", gin.WrapH(collector.Handler()))
 
-	// Setup routes
-	handlers.RegisterRoutes(router, service)
+	// Cap the number of reports accepted in one batch submission
+	maxBatchReports, err := strconv.Atoi(getEnv("MAX_BATCH_REPORTS", "100"))
+	if err != nil || maxBatchReports < 0 {
+		log.Fatalf("Invalid MAX_BATCH_REPORTS: %q", getEnv("MAX_BATCH_REPORTS", ""))
+	}
 
-	// Get server port from environment variables
+	// Get server port and shutdown drain timeout from environment variables
 	port := getEnv("SERVER_PORT", "8080")
+	drainTimeout, err := time.ParseDuration(getEnv("SHUTDOWN_TIMEOUT", "30s"))
+	if err != nil {
+		log.Fatalf("Invalid SHUTDOWN_TIMEOUT: %v", err)
+	}
+
+	// Setup routes on the server, whose readiness probe fails once it starts draining
+	srv := server.New(fmt.Sprintf(":%s", port), router, closers, drainTimeout, handlers.IngestPaths)
+	handlers.RegisterRoutes(router, service, handlers.Limits{MaxBatchReports: maxBatchReports, Ingest: ingest}, srv.Draining)
+
+	// Stop on SIGINT or SIGTERM, draining in-flight requests before closing the repository
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	// Start the server with configured port
 	log.Printf("Server starting on port %s", port)
-	if err := router.Run(fmt.Sprintf(":%s", port)); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	if err := srv.Run(ctx); err != nil {
+		log.Fatalf("Server stopped with error: %v", err)
 	}
+	log.Printf("Server stopped")
+}
+
+// openRepository opens the storage backend selected by STORAGE_BACKEND
+func openRepository(backend string) (application.Repository, error) {
+	switch backend {
+	case "mongodb":
+		return mongodb.NewMongoRepository(
+			getEnv("MONGODB_URI", "mongodb://localhost:27017"),
+			getEnv("MONGODB_DATABASE", "csp_scout"),
+			getEnv("MONGODB_COLLECTION", "reports"),
+		)
+	case "postgres":
+		return postgres.NewPostgresRepository(getEnv("POSTGRES_URL", "postgres://localhost:5432/csp_scout"))
+	case "memory":
+		return memory.NewMemoryRepository(), nil
+	case "sqlite":
+		return sqlite.NewSQLiteRepository(getEnv("SQLITE_PATH", "csp_scout.db"))
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_BACKEND %q, expected mongodb, postgres, sqlite or memory", backend)
+	}
+}
+
+// corsConfig allows browsers on origins to read the API and submit reports.
+// Without origins every origin is allowed.
+func corsConfig(origins []string) cors.Config {
+	config := cors.Config{
+		AllowMethods: []string{"GET", "POST", "OPTIONS"},
+		AllowHeaders: []string{"Origin", "Content-Type"},
+		MaxAge:       12 * time.Hour,
+	}
+	if len(origins) == 0 {
+		config.AllowAllOrigins = true
+	} else {
+		config.AllowOrigins = origins
+	}
+	return config
+}
+
+// noiseClassifier returns a classifier applying the default noise rules
+// followed by the rules in the JSON array at path, if set
+func noiseClassifier(path string) (*domain.NoiseClassifier, error) {
+	rules := domain.DefaultNoiseRules()
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var custom []domain.NoiseRule
+		if err := json.Unmarshal(data, &custom); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		rules = append(rules, custom...)
+	}
+	return domain.NewNoiseClassifier(rules)
 }
 
 func getEnv(key, fallback string) string {
@@ -63,3 +220,13 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(getEnv(key, ""), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -2,12 +2,16 @@ package application
 
 import (
 	"context"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
 )
 
 // Repository defines the complete repository interface combining all sub-repositories
 type Repository interface {
 	ReportsRepository
 	StatisticsRepository
+	IssuesRepository
+	HealthRepository
 	Close(ctx context.Context) error
 }
 
@@ -15,12 +19,20 @@ type Repository interface {
 type Service struct {
 	Reports    ReportsService
 	Statistics StatisticsService
+	Policies   PoliciesService
+	Issues     IssuesService
+	Health     HealthService
 }
 
-// NewService creates a new complete service instance
-func NewService(repo Repository) *Service {
+// NewService creates a new complete service instance. Ingested reports are
+// classified as noise by classifier, if not nil, and grouped into issues whose
+// regressions are sent to events, if not nil.
+func NewService(repo Repository, classifier *domain.NoiseClassifier, events IssueEventHandler) *Service {
 	return &Service{
-		Reports:    NewReportsService(repo),
+		Reports:    NewReportsService(repo, NewIssueRecorder(repo, events), classifier),
 		Statistics: NewStatisticsService(repo),
+		Policies:   NewPoliciesService(repo),
+		Issues:     NewIssuesService(repo, repo),
+		Health:     NewHealthService(repo),
 	}
 }
@@ -0,0 +1,17 @@
+package application
+
+import "errors"
+
+// Errors returned by services and repositories. Implementations wrap them with
+// details, so callers match them with errors.Is and must not show the wrapped
+// text to clients, except for ErrValidation whose details describe the input.
+var (
+	// ErrNotFound is returned when a requested resource does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrInvalidID is returned for IDs that are not well-formed
+	ErrInvalidID = errors.New("invalid ID")
+	// ErrValidation is returned when input is rejected as invalid
+	ErrValidation = errors.New("validation failed")
+	// ErrUnavailable is returned when the storage backend cannot be reached
+	ErrUnavailable = errors.New("storage unavailable")
+)
@@ -0,0 +1,61 @@
+package application
+
+import (
+	"context"
+	"time"
+)
+
+// maxPingBackoff caps the delay between connection attempts in WaitForRepository
+const maxPingBackoff = 30 * time.Second
+
+// HealthRepository defines health-specific repository methods
+type HealthRepository interface {
+	// Ping verifies that the storage backend is reachable
+	Ping(ctx context.Context) error
+}
+
+// HealthService defines health-specific service methods
+type HealthService interface {
+	CheckReadiness(ctx context.Context) error
+}
+
+type healthService struct {
+	repo HealthRepository
+}
+
+func NewHealthService(repo HealthRepository) HealthService {
+	return &healthService{
+		repo: repo,
+	}
+}
+
+// CheckReadiness reports whether the service can handle requests, which requires a reachable repository
+func (s *healthService) CheckReadiness(ctx context.Context) error {
+	return s.repo.Ping(ctx)
+}
+
+// WaitForRepository pings the repository up to attempts times, doubling the
+// delay between attempts starting at backoff, and returns the last error if
+// the repository never became reachable
+func WaitForRepository(ctx context.Context, repo HealthRepository, attempts int, backoff time.Duration, onRetry func(attempt int, err error)) error {
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = repo.Ping(ctx); err == nil {
+			return nil
+		}
+		if attempt == attempts {
+			break
+		}
+		if onRetry != nil {
+			onRetry(attempt, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxPingBackoff)
+	}
+	return err
+}
@@ -0,0 +1,53 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// flakyHealthRepository fails its first pings before becoming reachable
+type flakyHealthRepository struct {
+	failures int
+	pings    int
+}
+
+func (r *flakyHealthRepository) Ping(ctx context.Context) error {
+	r.pings++
+	if r.pings <= r.failures {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func TestWaitForRepository(t *testing.T) {
+	tests := []struct {
+		name          string
+		failures      int
+		attempts      int
+		expectedPings int
+		expectedErr   bool
+	}{
+		{name: "Reachable", failures: 0, attempts: 3, expectedPings: 1},
+		{name: "Recovers", failures: 2, attempts: 3, expectedPings: 3},
+		{name: "Unreachable", failures: 5, attempts: 3, expectedPings: 3, expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &flakyHealthRepository{failures: tt.failures}
+			retries := 0
+
+			err := WaitForRepository(context.Background(), repo, tt.attempts, time.Millisecond, func(attempt int, err error) {
+				retries++
+			})
+
+			assert.Equal(t, tt.expectedErr, err != nil)
+			assert.Equal(t, tt.expectedPings, repo.pings)
+			assert.Equal(t, tt.expectedPings-1, retries)
+		})
+	}
+}
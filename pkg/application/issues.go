@@ -0,0 +1,303 @@
+package application
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+)
+
+const (
+	// DefaultIssuesLimit is the page size used when an issue query does not set one
+	DefaultIssuesLimit = 50
+	// MaxIssuesLimit is the largest page size an issue query may request
+	MaxIssuesLimit = 1000
+)
+
+// IssueSort defines the order in which issues are listed
+type IssueSort string
+
+const (
+	// IssueSortCount lists the issues with the most reports first
+	IssueSortCount IssueSort = "count"
+	// IssueSortRecent lists the most recently seen issues first
+	IssueSortRecent IssueSort = "recent"
+)
+
+// ActiveIssueStatuses are the statuses of the issues that need attention,
+// listed when a query selects no statuses
+var ActiveIssueStatuses = []domain.IssueStatus{domain.IssueOpen, domain.IssueRegressed}
+
+// IssueQuery selects a page of issues
+type IssueQuery struct {
+	// Statuses selects the issues with any of the statuses
+	Statuses []domain.IssueStatus
+	Sort     IssueSort
+	Limit    int
+	// After continues the listing after the last issue of a previous page
+	After *IssueCursor
+	// IncludeTotal requests the number of matching issues in the page's Total
+	IncludeTotal bool
+}
+
+// Normalize applies the default statuses, page size and sort order and caps the page size
+func (q *IssueQuery) Normalize() {
+	if len(q.Statuses) == 0 {
+		q.Statuses = ActiveIssueStatuses
+	}
+	if q.Limit <= 0 {
+		q.Limit = DefaultIssuesLimit
+	}
+	if q.Limit > MaxIssuesLimit {
+		q.Limit = MaxIssuesLimit
+	}
+	if q.Sort != IssueSortRecent {
+		q.Sort = IssueSortCount
+	}
+}
+
+// Less reports whether issue a is listed before issue b. Ties in the sort
+// order are broken by fingerprint, so the order is total.
+func (s IssueSort) Less(a, b domain.Issue) bool {
+	if s == IssueSortRecent && a.LastSeen != b.LastSeen {
+		return a.LastSeen > b.LastSeen
+	}
+	if a.Count != b.Count {
+		return a.Count > b.Count
+	}
+	return a.Fingerprint < b.Fingerprint
+}
+
+// IssueCursor marks the last issue of a page by its position in the sort
+// order, so the next page continues after it rather than at an offset
+type IssueCursor struct {
+	Sort        IssueSort `json:"s"`
+	Count       int       `json:"c"`
+	LastSeen    int       `json:"l"`
+	Fingerprint string    `json:"f"`
+}
+
+// NewIssueCursor returns the cursor positioned at the given issue
+func NewIssueCursor(sort IssueSort, issue domain.Issue) *IssueCursor {
+	return &IssueCursor{
+		Sort:        sort,
+		Count:       issue.Count,
+		LastSeen:    issue.LastSeen,
+		Fingerprint: issue.Fingerprint,
+	}
+}
+
+// Encode returns the opaque string representation of the cursor
+func (c IssueCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Follows reports whether issue is listed after the cursor
+func (c IssueCursor) Follows(issue domain.Issue) bool {
+	return c.Sort.Less(domain.Issue{Fingerprint: c.Fingerprint, Count: c.Count, LastSeen: c.LastSeen}, issue)
+}
+
+// DecodeIssueCursor parses an opaque cursor previously returned by Encode
+func DecodeIssueCursor(value string) (*IssueCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor IssueCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if cursor.Sort != IssueSortCount && cursor.Sort != IssueSortRecent || !domain.IsFingerprint(cursor.Fingerprint) {
+		return nil, ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
+
+// IssuePage is a single page of issues. The next page continues after the
+// last issue of this one, so issues are neither skipped nor repeated while
+// their position is unchanged. An issue whose count or last report time
+// changes between requests may move across the cursor and be skipped or
+// listed again.
+type IssuePage struct {
+	Issues []domain.Issue
+	// Next is set when more issues follow this page
+	Next *IssueCursor
+	// Total is the number of matching issues, set when requested
+	Total *int64
+}
+
+// IssueEventType names an issue event
+type IssueEventType string
+
+// IssueEventRegressed is sent when a resolved issue receives reports after its resolution
+const IssueEventRegressed IssueEventType = "issue.regressed"
+
+// IssueEvent is a change of an issue that happened without triage
+type IssueEvent struct {
+	Type  IssueEventType `json:"type"`
+	Issue domain.Issue   `json:"issue"`
+}
+
+// IssueEventHandler is notified of issue events. Implementations must not
+// block ingestion for long, e.g. by delivering events asynchronously.
+type IssueEventHandler interface {
+	HandleIssueEvent(ctx context.Context, event IssueEvent)
+}
+
+// IssuesRepository defines issue-specific repository methods
+type IssuesRepository interface {
+	// RecordIssues adds issues to the stored issues with the same fingerprints
+	// as domain.Issue.Record does and stores the others. It returns the
+	// fingerprints of the issues that regressed.
+	RecordIssues(ctx context.Context, issues []domain.Issue) ([]string, error)
+	// GetIssue returns ErrNotFound when no issue has the fingerprint
+	GetIssue(ctx context.Context, fingerprint string) (*domain.Issue, error)
+	ListIssues(ctx context.Context, query IssueQuery) (*IssuePage, error)
+	// SetIssueStatus applies a triage decision as domain.Issue.SetStatus does
+	// and returns the updated issue, or ErrNotFound when no issue has the fingerprint
+	SetIssueStatus(ctx context.Context, fingerprint string, change domain.IssueStatusChange) (*domain.Issue, error)
+}
+
+// IssuesService defines issue-specific service methods
+type IssuesService interface {
+	ListIssues(ctx context.Context, query IssueQuery) (*IssuePage, error)
+	GetIssue(ctx context.Context, fingerprint string) (*domain.Issue, error)
+	// ListIssueReports returns a page of the reports of an issue, or
+	// ErrNotFound when no issue has the fingerprint
+	ListIssueReports(ctx context.Context, fingerprint string, query ReportQuery) (*ReportPage, error)
+	// UpdateIssueStatus applies a triage decision made now. An invalid
+	// decision is rejected with an ErrValidation wrapping a *domain.ValidationError.
+	UpdateIssueStatus(ctx context.Context, fingerprint string, change domain.IssueStatusChange) (*domain.Issue, error)
+}
+
+type issuesService struct {
+	issues  IssuesRepository
+	reports ReportsRepository
+	now     func() time.Time
+}
+
+func NewIssuesService(issues IssuesRepository, reports ReportsRepository) IssuesService {
+	return &issuesService{
+		issues:  issues,
+		reports: reports,
+		now:     time.Now,
+	}
+}
+
+// ListIssues rejects a cursor of a different sort order with ErrInvalidCursor
+func (s *issuesService) ListIssues(ctx context.Context, query IssueQuery) (*IssuePage, error) {
+	query.Normalize()
+	if query.After != nil && query.After.Sort != query.Sort {
+		return nil, ErrInvalidCursor
+	}
+	return s.issues.ListIssues(ctx, query)
+}
+
+// GetIssue returns the issue with the given fingerprint, rejecting malformed
+// fingerprints with ErrInvalidID before querying the repository
+func (s *issuesService) GetIssue(ctx context.Context, fingerprint string) (*domain.Issue, error) {
+	if !domain.IsFingerprint(fingerprint) {
+		return nil, ErrInvalidID
+	}
+	return s.issues.GetIssue(ctx, fingerprint)
+}
+
+// ListIssueReports lists the reports with the issue's fingerprint. The
+// query's filter narrows them further, e.g. to a time window.
+func (s *issuesService) ListIssueReports(ctx context.Context, fingerprint string, query ReportQuery) (*ReportPage, error) {
+	if _, err := s.GetIssue(ctx, fingerprint); err != nil {
+		return nil, err
+	}
+
+	query.Filter.Fingerprint = &StringFilter{Value: fingerprint, Match: MatchExact}
+	query.Normalize()
+	return s.reports.ListReports(ctx, query)
+}
+
+func (s *issuesService) UpdateIssueStatus(ctx context.Context, fingerprint string, change domain.IssueStatusChange) (*domain.Issue, error) {
+	if !domain.IsFingerprint(fingerprint) {
+		return nil, ErrInvalidID
+	}
+	if err := change.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	change.Time = int(s.now().Unix())
+	return s.issues.SetIssueStatus(ctx, fingerprint, change)
+}
+
+// IssueRecorder counts ingested reports in their issues and notifies events
+// of the issues that regress
+type IssueRecorder struct {
+	repo    IssuesRepository
+	events  IssueEventHandler
+	onError func(err error)
+}
+
+// NewIssueRecorder creates a recorder storing issues in repo. Events are
+// dropped when events is nil.
+func NewIssueRecorder(repo IssuesRepository, events IssueEventHandler) *IssueRecorder {
+	return &IssueRecorder{
+		repo:   repo,
+		events: events,
+		onError: func(err error) {
+			log.Printf("Warning: %v", err)
+		},
+	}
+}
+
+// Record counts reports with a fingerprint in their issues. The reports are
+// already stored, so recording is best-effort: failures are logged rather than
+// returned, so that clients don't retry and store the reports twice. Failed
+// repository operations are still counted by the repository metrics.
+func (r *IssueRecorder) Record(ctx context.Context, reports []domain.Report) {
+	issues := issuesOf(reports)
+	if len(issues) == 0 {
+		return
+	}
+
+	regressed, err := r.repo.RecordIssues(ctx, issues)
+	if err != nil {
+		r.onError(fmt.Errorf("record issues of %d reports: %w", len(reports), err))
+		return
+	}
+	if r.events == nil {
+		return
+	}
+
+	for _, fingerprint := range regressed {
+		issue, err := r.repo.GetIssue(ctx, fingerprint)
+		if err != nil {
+			r.onError(fmt.Errorf("load regressed issue %s: %w", fingerprint, err))
+			continue
+		}
+		r.events.HandleIssueEvent(ctx, IssueEvent{Type: IssueEventRegressed, Issue: *issue})
+	}
+}
+
+// issuesOf groups reports by fingerprint into the issues they add to, in the
+// order the fingerprints first occur. Reports without a fingerprint are skipped.
+func issuesOf(reports []domain.Report) []domain.Issue {
+	var issues []domain.Issue
+	index := make(map[string]int)
+	for i := range reports {
+		data := reports[i].Report
+		if data.Fingerprint == "" {
+			continue
+		}
+		if j, ok := index[data.Fingerprint]; ok {
+			issues[j].Merge(domain.NewIssue(data))
+			continue
+		}
+		index[data.Fingerprint] = len(issues)
+		issues = append(issues, domain.NewIssue(data))
+	}
+	return issues
+}
@@ -0,0 +1,159 @@
+package application_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/infrastructure/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceIssues(t *testing.T) {
+	service := application.NewService(memory.NewMemoryRepository(), domain.DefaultNoiseClassifier(), nil)
+	ctx := context.Background()
+
+	report := func(documentURI, blockedURI string, reportTime int) domain.Report {
+		return domain.Report{Report: domain.ReportData{
+			DocumentUri:        documentURI,
+			EffectiveDirective: "script-src-elem",
+			BlockedUri:         blockedURI,
+			ReportTime:         reportTime,
+		}}
+	}
+	require.NoError(t, service.Reports.CreateReports(ctx, []domain.Report{
+		report("https://example.com/orders/1", "https://cdn.example.net/app.js", 300),
+		report("https://example.com/orders/2", "https://cdn.example.net/vendor.js", 100),
+		report("https://example.com/cart", "https://cdn.example.net/app.js", 200),
+		// Noise is not grouped into issues
+		report("https://example.com/cart", "chrome-extension://abcdef/inject.js", 400),
+	}))
+	single := report("https://example.com/orders/3?ref=mail", "https://cdn.example.net/app.js", 500)
+	require.NoError(t, service.Reports.CreateReport(ctx, &single))
+
+	page, err := service.Issues.ListIssues(ctx, application.IssueQuery{})
+	require.NoError(t, err)
+	require.Len(t, page.Issues, 2)
+
+	orders := page.Issues[0]
+	assert.Equal(t, "example.com/orders/{id}", orders.DocumentPath)
+	assert.Equal(t, "https://cdn.example.net", orders.BlockedOrigin)
+	assert.Equal(t, 100, orders.FirstSeen)
+	assert.Equal(t, 500, orders.LastSeen)
+	assert.Equal(t, 3, orders.Count)
+	assert.Equal(t, orders.Fingerprint, single.Report.Fingerprint)
+
+	recent, err := service.Issues.ListIssues(ctx, application.IssueQuery{Sort: application.IssueSortRecent, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Issue{orders}, recent.Issues)
+	require.NotNil(t, recent.Next)
+
+	// A cursor continues only the sort order it was returned for
+	_, err = service.Issues.ListIssues(ctx, application.IssueQuery{Sort: application.IssueSortCount, After: recent.Next})
+	assert.ErrorIs(t, err, application.ErrInvalidCursor)
+
+	reports, err := service.Issues.ListIssueReports(ctx, orders.Fingerprint, application.ReportQuery{Sort: application.SortOldest})
+	require.NoError(t, err)
+	require.Len(t, reports.Reports, 3)
+	assert.Equal(t, 100, reports.Reports[0].Report.ReportTime)
+	assert.Equal(t, 500, reports.Reports[2].Report.ReportTime)
+
+	_, err = service.Issues.GetIssue(ctx, "not-a-fingerprint")
+	assert.ErrorIs(t, err, application.ErrInvalidID)
+	_, err = service.Issues.ListIssueReports(ctx, "ffffffffffffffffffffffffffffffff", application.ReportQuery{})
+	assert.ErrorIs(t, err, application.ErrNotFound)
+}
+
+// recordingEvents collects the issue events it receives
+type recordingEvents struct {
+	events []application.IssueEvent
+}
+
+func (r *recordingEvents) HandleIssueEvent(ctx context.Context, event application.IssueEvent) {
+	r.events = append(r.events, event)
+}
+
+func TestServiceIssueLifecycle(t *testing.T) {
+	events := &recordingEvents{}
+	service := application.NewService(memory.NewMemoryRepository(), domain.DefaultNoiseClassifier(), events)
+	ctx := context.Background()
+
+	report := func(reportTime int) domain.Report {
+		return domain.Report{Report: domain.ReportData{
+			DocumentUri:        "https://example.com/checkout",
+			EffectiveDirective: "script-src-elem",
+			BlockedUri:         "https://cdn.example.net/app.js",
+			ReportTime:         reportTime,
+		}}
+	}
+	first := report(100)
+	require.NoError(t, service.Reports.CreateReport(ctx, &first))
+	fingerprint := first.Report.Fingerprint
+
+	_, err := service.Issues.UpdateIssueStatus(ctx, fingerprint, domain.IssueStatusChange{Status: domain.IssueResolved})
+	var validationErr *domain.ValidationError
+	assert.ErrorIs(t, err, application.ErrValidation)
+	assert.ErrorAs(t, err, &validationErr)
+	_, err = service.Issues.UpdateIssueStatus(ctx, "nope", domain.IssueStatusChange{Status: domain.IssueResolved, Actor: "alice"})
+	assert.ErrorIs(t, err, application.ErrInvalidID)
+
+	resolved, err := service.Issues.UpdateIssueStatus(ctx, fingerprint, domain.IssueStatusChange{
+		Status: domain.IssueResolved, Note: "allowed in the policy", Actor: "alice",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, domain.IssueResolved, resolved.Status)
+	assert.Greater(t, resolved.StatusTime, 0)
+
+	// Resolved issues are not listed by default
+	page, err := service.Issues.ListIssues(ctx, application.IssueQuery{})
+	require.NoError(t, err)
+	assert.Empty(t, page.Issues)
+
+	// A delayed report from before the resolution doesn't reopen the issue
+	require.NoError(t, service.Reports.CreateReports(ctx, []domain.Report{report(200)}))
+	assert.Empty(t, events.events)
+
+	later := resolved.StatusTime + 60
+	require.NoError(t, service.Reports.CreateReports(ctx, []domain.Report{report(later), report(later + 1)}))
+	require.Len(t, events.events, 1)
+	assert.Equal(t, application.IssueEventRegressed, events.events[0].Type)
+	assert.Equal(t, fingerprint, events.events[0].Issue.Fingerprint)
+	assert.Equal(t, domain.IssueRegressed, events.events[0].Issue.Status)
+	assert.Equal(t, later+1, events.events[0].Issue.RegressedAt)
+
+	page, err = service.Issues.ListIssues(ctx, application.IssueQuery{})
+	require.NoError(t, err)
+	require.Len(t, page.Issues, 1)
+	assert.Equal(t, 4, page.Issues[0].Count)
+}
+
+// failingIssuesRepository stores reports but fails to record their issues
+type failingIssuesRepository struct {
+	application.Repository
+}
+
+func (r failingIssuesRepository) RecordIssues(ctx context.Context, issues []domain.Issue) ([]string, error) {
+	return nil, errors.New("issues table locked")
+}
+
+func TestServiceIssuesBestEffort(t *testing.T) {
+	service := application.NewService(failingIssuesRepository{memory.NewMemoryRepository()}, domain.DefaultNoiseClassifier(), nil)
+	ctx := context.Background()
+
+	report := domain.Report{Report: domain.ReportData{
+		DocumentUri:        "https://example.com/checkout",
+		EffectiveDirective: "script-src-elem",
+		BlockedUri:         "https://cdn.example.net/app.js",
+		ReportTime:         100,
+	}}
+	// The stored reports are not rejected, which would make clients send them again
+	require.NoError(t, service.Reports.CreateReport(ctx, &report))
+	require.NoError(t, service.Reports.CreateReports(ctx, []domain.Report{report}))
+
+	page, err := service.Reports.ListReports(ctx, application.ReportQuery{})
+	require.NoError(t, err)
+	assert.Len(t, page.Reports, 2)
+}
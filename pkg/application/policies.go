@@ -0,0 +1,257 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain/csp"
+)
+
+// PolicyRecommendationParams selects the reports a policy recommendation is based on
+type PolicyRecommendationParams struct {
+	// Filter selects the documents and the time window, e.g. by a document URI
+	// prefix and from and to. Noise is excluded unless the filter selects it.
+	Filter ReportFilter
+	// MinCount is the number of reports a source needs before it is added; values below 1 mean 1
+	MinCount int
+}
+
+// SourceAddition is a source the recommendation adds to a directive, with the
+// number of reports it was blocked in
+type SourceAddition struct {
+	Directive string `json:"directive"`
+	Source    string `json:"source"`
+	Count     int    `json:"count"`
+}
+
+// ExclusionReason explains why a blocked value was not added to the policy
+type ExclusionReason string
+
+const (
+	ExcludedExtension      ExclusionReason = "browser extension"
+	ExcludedInline         ExclusionReason = "inline code needs a nonce or hash"
+	ExcludedEval           ExclusionReason = "eval is never allowed automatically"
+	ExcludedUnsupported    ExclusionReason = "no source expression allows it"
+	ExcludedAlreadyAllowed ExclusionReason = "already allowed"
+	ExcludedTooFewReports  ExclusionReason = "too few reports"
+)
+
+// ExcludedSource is a blocked value, or the source it maps to, that the
+// recommendation does not add
+type ExcludedSource struct {
+	Directive string          `json:"directive"`
+	Value     string          `json:"value"`
+	Count     int             `json:"count"`
+	Reason    ExclusionReason `json:"reason"`
+}
+
+// PolicyRecommendation is a revised policy that allows what the matching reports were blocked from loading
+type PolicyRecommendation struct {
+	// OriginalPolicy is the policy of the most recent matching report
+	OriginalPolicy string `json:"originalpolicy"`
+	// Policy is the revised policy in the header format
+	Policy    string           `json:"policy"`
+	Additions []SourceAddition `json:"additions"`
+	Excluded  []ExcludedSource `json:"excluded"`
+}
+
+// PoliciesRepository defines the repository methods policy recommendations are built from
+type PoliciesRepository interface {
+	ListReports(ctx context.Context, query ReportQuery) (*ReportPage, error)
+	GetTopEffectiveDirectives(ctx context.Context, params StatisticsParams) ([]TopDirectiveResult, error)
+	GetTopBlockedURIs(ctx context.Context, params StatisticsParams) ([]TopBlockedURIResult, error)
+}
+
+// PoliciesService defines policy-specific service methods
+type PoliciesService interface {
+	// RecommendPolicy returns ErrNotFound when no report matches the filter
+	RecommendPolicy(ctx context.Context, params PolicyRecommendationParams) (*PolicyRecommendation, error)
+}
+
+type policiesService struct {
+	repo PoliciesRepository
+}
+
+func NewPoliciesService(repo PoliciesRepository) PoliciesService {
+	return &policiesService{
+		repo: repo,
+	}
+}
+
+// candidate is a source collected for a directive before the recommendation decides on it
+type candidate struct {
+	directive string
+	source    string
+	count     int
+}
+
+// RecommendPolicy takes the policy of the most recent matching report and adds
+// the origins blocked per effective directive. Each source is added to the
+// directive that governs the effective directive, so a blocked script-src-elem
+// load extends script-src when the policy has no script-src-elem. Rather than
+// widening default-src, a directive only governed by it is added, seeded with
+// the default-src sources. Reports classified as noise are ignored unless the
+// filter selects them. Extension URIs, inline code and eval are never allowed;
+// they are listed as excluded.
+func (s *policiesService) RecommendPolicy(ctx context.Context, params PolicyRecommendationParams) (*PolicyRecommendation, error) {
+	if params.MinCount < 1 {
+		params.MinCount = 1
+	}
+	params.Filter = params.Filter.excludingNoiseByDefault()
+
+	latest, err := s.repo.ListReports(ctx, ReportQuery{Filter: params.Filter, Limit: 1, Sort: SortNewest})
+	if err != nil {
+		return nil, err
+	}
+	if len(latest.Reports) == 0 {
+		return nil, fmt.Errorf("%w: no reports match", ErrNotFound)
+	}
+
+	recommendation := &PolicyRecommendation{
+		OriginalPolicy: latest.Reports[0].Report.OriginalPolicy,
+		Additions:      []SourceAddition{},
+		Excluded:       []ExcludedSource{},
+	}
+	policy := csp.Parse(recommendation.OriginalPolicy)
+
+	directives, err := s.repo.GetTopEffectiveDirectives(ctx, StatisticsParams{Limit: MaxStatisticsLimit, Filter: params.Filter})
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []*candidate
+	byKey := make(map[string]*candidate)
+	for _, directive := range directives {
+		if !csp.IsSourceList(directive.Directive) {
+			continue
+		}
+		target := directive.Directive
+		if governing := policy.Governing(target); governing != nil && governing.Name != defaultSrc {
+			target = governing.Name
+		} else {
+			// Element and attribute directives are recommended on their base directive, e.g. script-src
+			target = strings.TrimSuffix(strings.TrimSuffix(target, "-elem"), "-attr")
+		}
+
+		filter := params.Filter
+		filter.EffectiveDirective = &StringFilter{Value: directive.Directive, Match: MatchExact}
+		blocked, err := s.repo.GetTopBlockedURIs(ctx, StatisticsParams{Limit: MaxStatisticsLimit, Filter: filter, NormalizeOrigin: true})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, uri := range blocked {
+			source, reason := sourceFor(uri.BlockedURI)
+			if reason != "" {
+				recommendation.Excluded = append(recommendation.Excluded, ExcludedSource{
+					Directive: target, Value: uri.BlockedURI, Count: uri.Count, Reason: reason,
+				})
+				continue
+			}
+
+			// Blocked values that map to the same source, e.g. several data: URLs, are counted together
+			key := target + " " + source
+			if c, ok := byKey[key]; ok {
+				c.count += uri.Count
+				continue
+			}
+			c := &candidate{directive: target, source: source, count: uri.Count}
+			byKey[key] = c
+			candidates = append(candidates, c)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].count != candidates[j].count {
+			return candidates[i].count > candidates[j].count
+		}
+		return candidates[i].source < candidates[j].source
+	})
+
+	for _, c := range candidates {
+		governing := policy.Governing(c.directive)
+		if governing != nil && governing.HasSource(c.source) {
+			recommendation.Excluded = append(recommendation.Excluded, ExcludedSource{
+				Directive: c.directive, Value: c.source, Count: c.count, Reason: ExcludedAlreadyAllowed,
+			})
+			continue
+		}
+		if c.count < params.MinCount {
+			recommendation.Excluded = append(recommendation.Excluded, ExcludedSource{
+				Directive: c.directive, Value: c.source, Count: c.count, Reason: ExcludedTooFewReports,
+			})
+			continue
+		}
+
+		directive := policy.Directive(c.directive)
+		if directive == nil {
+			added := csp.Directive{Name: c.directive, Sources: []csp.Source{}}
+			if governing != nil {
+				added.Sources = append(added.Sources, governing.Sources...)
+			}
+			policy.Directives = append(policy.Directives, added)
+			directive = &policy.Directives[len(policy.Directives)-1]
+		}
+
+		directive.RemoveKeyword(csp.KeywordNone)
+		directive.Sources = append(directive.Sources, csp.ParseSource(c.source))
+		recommendation.Additions = append(recommendation.Additions, SourceAddition{
+			Directive: c.directive, Source: c.source, Count: c.count,
+		})
+	}
+
+	sort.SliceStable(recommendation.Excluded, func(i, j int) bool {
+		return recommendation.Excluded[i].Count > recommendation.Excluded[j].Count
+	})
+	recommendation.Policy = policy.String()
+	return recommendation, nil
+}
+
+// defaultSrc is the directive every fetch directive falls back to
+const defaultSrc = "default-src"
+
+// schemeOnlySources lists the schemes whose URLs can only be allowed by a scheme source
+var schemeOnlySources = map[string]bool{
+	"blob":        true,
+	"data":        true,
+	"filesystem":  true,
+	"mediastream": true,
+}
+
+// urlScheme matches the scheme of a URL
+var urlScheme = regexp.MustCompile(`^[a-z][a-z0-9+.-]*$`)
+
+// sourceFor returns the source expression that allows a blocked URI, already
+// normalized to its origin, or the reason no source is recommended for it
+func sourceFor(blockedURI string) (string, ExclusionReason) {
+	value := strings.ToLower(blockedURI)
+	switch {
+	case domain.IsExtensionURI(value):
+		return "", ExcludedExtension
+	case value == "inline":
+		return "", ExcludedInline
+	case value == "eval" || value == "wasm-eval":
+		return "", ExcludedEval
+	case value == "self":
+		return csp.KeywordSelf, ""
+	case schemeOnlySources[value]:
+		// Older browsers only report the scheme of data: and blob: URLs
+		return value + ":", ""
+	}
+
+	scheme, rest, found := strings.Cut(value, ":")
+	if !found || !urlScheme.MatchString(scheme) {
+		return "", ExcludedUnsupported
+	}
+	if schemeOnlySources[scheme] {
+		return scheme + ":", ""
+	}
+	if strings.HasPrefix(rest, "//") && csp.ParseSource(value).Kind == csp.KindHost {
+		return value, ""
+	}
+	return "", ExcludedUnsupported
+}
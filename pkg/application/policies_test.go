@@ -0,0 +1,93 @@
+package application_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/infrastructure/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecommendPolicy(t *testing.T) {
+	service := application.NewService(memory.NewMemoryRepository(), domain.DefaultNoiseClassifier(), nil)
+	ctx := context.Background()
+
+	const policy = "default-src 'none'; script-src 'self' https://static.example.com; report-uri /csp"
+	blocked := []struct {
+		directive string
+		uri       string
+		times     int
+	}{
+		{"script-src-elem", "https://cdn.example.net/app.js", 3},
+		{"script-src-elem", "https://cdn.example.net/vendor.js", 2},
+		{"script-src-elem", "https://static.example.com/late.js", 1},
+		{"script-src-elem", "chrome-extension://abcdef/inject.js", 4},
+		{"script-src-elem", "inline", 2},
+		{"img-src", "data", 1},
+		{"img-src", "data:image/png;base64,AAAA", 1},
+		{"img-src", "https://pixel.example.org/p.gif", 1},
+		{"require-trusted-types-for", "trusted-types-sink", 1},
+	}
+
+	var reports []domain.Report
+	for i, b := range blocked {
+		for n := 0; n < b.times; n++ {
+			reports = append(reports, domain.Report{Report: domain.ReportData{
+				DocumentUri:        "https://example.com/checkout",
+				EffectiveDirective: b.directive,
+				BlockedUri:         b.uri,
+				OriginalPolicy:     policy,
+				ReportTime:         1700000000 + i,
+			}})
+		}
+	}
+	// Reports of other documents are not considered
+	reports = append(reports, domain.Report{Report: domain.ReportData{
+		DocumentUri:        "https://other.example.com/",
+		EffectiveDirective: "font-src",
+		BlockedUri:         "https://fonts.example.net/a.woff",
+		OriginalPolicy:     "default-src 'self'",
+		ReportTime:         1700000100,
+	}})
+	require.NoError(t, service.Reports.CreateReports(ctx, reports))
+
+	recommendation, err := service.Policies.RecommendPolicy(ctx, application.PolicyRecommendationParams{
+		Filter:   application.ReportFilter{DocumentUri: &application.StringFilter{Value: "https://example.com/", Match: application.MatchPrefix}},
+		MinCount: 2,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, policy, recommendation.OriginalPolicy)
+	assert.Equal(t, "default-src 'none'; script-src 'self' https://static.example.com https://cdn.example.net; report-uri /csp; img-src data:",
+		recommendation.Policy)
+	assert.Equal(t, []application.SourceAddition{
+		{Directive: "script-src", Source: "https://cdn.example.net", Count: 5},
+		{Directive: "img-src", Source: "data:", Count: 2},
+	}, recommendation.Additions)
+	// Extension reports are classified as noise on ingest and left out
+	assert.ElementsMatch(t, []application.ExcludedSource{
+		{Directive: "script-src", Value: "inline", Count: 2, Reason: application.ExcludedInline},
+		{Directive: "script-src", Value: "https://static.example.com", Count: 1, Reason: application.ExcludedAlreadyAllowed},
+		{Directive: "img-src", Value: "https://pixel.example.org", Count: 1, Reason: application.ExcludedTooFewReports},
+	}, recommendation.Excluded)
+
+	noise, err := service.Policies.RecommendPolicy(ctx, application.PolicyRecommendationParams{
+		Filter: application.ReportFilter{Noise: application.NoiseOnly},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, noise.Additions)
+	assert.Equal(t, []application.ExcludedSource{
+		{Directive: "script-src", Value: "chrome-extension://abcdef", Count: 4, Reason: application.ExcludedExtension},
+	}, noise.Excluded)
+}
+
+func TestRecommendPolicyWithoutReports(t *testing.T) {
+	service := application.NewService(memory.NewMemoryRepository(), domain.DefaultNoiseClassifier(), nil)
+
+	_, err := service.Policies.RecommendPolicy(context.Background(), application.PolicyRecommendationParams{})
+	assert.True(t, errors.Is(err, application.ErrNotFound))
+}
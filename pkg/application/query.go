@@ -0,0 +1,194 @@
+package application
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// DefaultReportsLimit is the page size used when a query does not set one
+	DefaultReportsLimit = 100
+	// MaxReportsLimit is the largest page size a query may request
+	MaxReportsLimit = 1000
+)
+
+// ErrInvalidCursor is returned when a continuation cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// SortOrder defines the order in which reports are listed by report time
+type SortOrder string
+
+const (
+	SortNewest SortOrder = "desc"
+	SortOldest SortOrder = "asc"
+)
+
+// ReportCursor marks the last report of a page. Reports are ordered by report
+// time and ID, so the cursor is a keyset position rather than an offset.
+type ReportCursor struct {
+	ReportTime int    `json:"t"`
+	ID         string `json:"id"`
+}
+
+// NewReportCursor returns the cursor positioned at the given report
+func NewReportCursor(report domain.Report) *ReportCursor {
+	return &ReportCursor{
+		ReportTime: report.Report.ReportTime,
+		ID:         report.ID.Hex(),
+	}
+}
+
+// Encode returns the opaque string representation of the cursor
+func (c ReportCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses an opaque cursor previously returned by Encode
+func DecodeCursor(value string) (*ReportCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor ReportCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if _, err := primitive.ObjectIDFromHex(cursor.ID); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
+
+// MatchType defines how a string filter is compared against a report field
+type MatchType string
+
+const (
+	MatchExact    MatchType = "exact"
+	MatchPrefix   MatchType = "prefix"
+	MatchContains MatchType = "contains"
+)
+
+// StringFilter matches a report field against a value
+type StringFilter struct {
+	Value string
+	Match MatchType
+}
+
+// FieldFilter is a StringFilter bound to a report field, named by one of the domain.Field constants
+type FieldFilter struct {
+	Field string
+	StringFilter
+}
+
+// NoiseFilter selects reports by whether they were classified as noise
+type NoiseFilter string
+
+const (
+	// NoiseInclude matches every report, as does an unset NoiseFilter
+	NoiseInclude NoiseFilter = "include"
+	// NoiseExclude matches reports that are not noise
+	NoiseExclude NoiseFilter = "exclude"
+	// NoiseOnly matches reports classified as noise
+	NoiseOnly NoiseFilter = "only"
+)
+
+// Matches reports whether a report of the noise category satisfies the filter
+func (f NoiseFilter) Matches(category string) bool {
+	switch f {
+	case NoiseExclude:
+		return category == ""
+	case NoiseOnly:
+		return category != ""
+	default:
+		return true
+	}
+}
+
+// ReportFilter restricts the reports a query applies to. Unset filters match every report.
+type ReportFilter struct {
+	DocumentUri        *StringFilter
+	ViolatedDirective  *StringFilter
+	EffectiveDirective *StringFilter
+	BlockedUri         *StringFilter
+	SourceFile         *StringFilter
+	Disposition        *StringFilter
+	NoiseCategory      *StringFilter
+	Fingerprint        *StringFilter
+	Noise              NoiseFilter
+	// From and To bound the report time in Unix seconds. From is inclusive, To
+	// is exclusive and zero leaves the range open on that side.
+	From int
+	To   int
+}
+
+// FieldFilters returns the string filters that are set, keyed by report field
+func (f ReportFilter) FieldFilters() []FieldFilter {
+	candidates := []struct {
+		field  string
+		filter *StringFilter
+	}{
+		{domain.FieldDocumentUri, f.DocumentUri},
+		{domain.FieldViolatedDirective, f.ViolatedDirective},
+		{domain.FieldEffectiveDirective, f.EffectiveDirective},
+		{domain.FieldBlockedUri, f.BlockedUri},
+		{domain.FieldSourceFile, f.SourceFile},
+		{domain.FieldDisposition, f.Disposition},
+		{domain.FieldNoiseCategory, f.NoiseCategory},
+		{domain.FieldFingerprint, f.Fingerprint},
+	}
+
+	var filters []FieldFilter
+	for _, candidate := range candidates {
+		if candidate.filter != nil {
+			filters = append(filters, FieldFilter{Field: candidate.field, StringFilter: *candidate.filter})
+		}
+	}
+	return filters
+}
+
+// excludingNoiseByDefault returns the filter with noise excluded unless it
+// selects noise itself, by Noise or by a NoiseCategory filter
+func (f ReportFilter) excludingNoiseByDefault() ReportFilter {
+	if f.Noise == "" && f.NoiseCategory == nil {
+		f.Noise = NoiseExclude
+	}
+	return f
+}
+
+// ReportQuery defines which page of reports to list
+type ReportQuery struct {
+	Filter       ReportFilter
+	Limit        int
+	Sort         SortOrder
+	After        *ReportCursor
+	IncludeTotal bool
+}
+
+// Normalize applies the default page size and sort order and caps the page size
+func (q *ReportQuery) Normalize() {
+	if q.Limit <= 0 {
+		q.Limit = DefaultReportsLimit
+	}
+	if q.Limit > MaxReportsLimit {
+		q.Limit = MaxReportsLimit
+	}
+	if q.Sort != SortOldest {
+		q.Sort = SortNewest
+	}
+}
+
+// ReportPage is a single page of reports
+type ReportPage struct {
+	Reports []domain.Report
+	// Next is set when more reports follow this page
+	Next *ReportCursor
+	// Total is the number of reports matching the query, set when requested
+	Total *int64
+}
@@ -2,42 +2,134 @@ package application
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // ReportsRepository defines reports-specific repository methods
 type ReportsRepository interface {
 	CreateReport(ctx context.Context, report *domain.Report) error
+	CreateReports(ctx context.Context, reports []domain.Report) error
+	// GetReport returns ErrInvalidID for IDs that are not valid report IDs and
+	// ErrNotFound when no report has the ID
 	GetReport(ctx context.Context, id string) (*domain.Report, error)
-	ListReports(ctx context.Context) ([]domain.Report, error)
+	ListReports(ctx context.Context, query ReportQuery) (*ReportPage, error)
 }
 
 // ReportsService defines reports-specific service methods
 type ReportsService interface {
 	CreateReport(ctx context.Context, report *domain.Report) error
+	CreateReports(ctx context.Context, reports []domain.Report) error
 	GetReport(ctx context.Context, id string) (*domain.Report, error)
-	ListReports(ctx context.Context) ([]domain.Report, error)
+	ListReports(ctx context.Context, query ReportQuery) (*ReportPage, error)
 }
 
 type reportsService struct {
-	repo ReportsRepository
+	repo       ReportsRepository
+	issues     *IssueRecorder
+	classifier *domain.NoiseClassifier
 }
 
-func NewReportsService(repo ReportsRepository) ReportsService {
+// NewReportsService creates the reports service. Reports are classified as
+// noise by classifier on ingest, or left unclassified if it is nil. Reports
+// that are not noise are counted in their issue by issues, if not nil.
+func NewReportsService(repo ReportsRepository, issues *IssueRecorder, classifier *domain.NoiseClassifier) ReportsService {
 	return &reportsService{
-		repo: repo,
+		repo:       repo,
+		issues:     issues,
+		classifier: classifier,
 	}
 }
 
+// classify sets the noise classification and the fingerprint of a report,
+// replacing any set by the client. Noise is not grouped into issues.
+func (s *reportsService) classify(data *domain.ReportData) {
+	if s.classifier != nil {
+		s.classifier.Classify(data)
+	} else {
+		data.NoiseCategory, data.NoiseReason = "", ""
+	}
+
+	data.Fingerprint = ""
+	if s.issues != nil && data.NoiseCategory == "" {
+		data.Fingerprint = domain.NewIssue(*data).Fingerprint
+	}
+}
+
+// recordIssues counts stored reports in their issues. It must not fail the
+// ingest, as the reports are already stored.
+func (s *reportsService) recordIssues(ctx context.Context, reports []domain.Report) {
+	if s.issues != nil {
+		s.issues.Record(ctx, reports)
+	}
+}
+
+// CreateReport validates a report and stores it under a freshly generated ID,
+// ignoring any ID set by the caller. Overlong free-text fields are truncated;
+// an invalid report is rejected with an ErrValidation wrapping a *domain.ValidationError.
+// Valid reports are classified as noise before they are stored and are then
+// counted in their issue on a best-effort basis.
 func (s *reportsService) CreateReport(ctx context.Context, report *domain.Report) error {
-	return s.repo.CreateReport(ctx, report)
+	report.Report.Truncate()
+	if err := report.Report.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+	s.classify(&report.Report)
+
+	report.ID = primitive.NewObjectID()
+	if err := s.repo.CreateReport(ctx, report); err != nil {
+		return err
+	}
+	s.recordIssues(ctx, []domain.Report{*report})
+	return nil
 }
 
+// CreateReports validates a batch of reports and stores them, each under a
+// freshly generated ID. If any report is invalid, none are stored and the
+// field errors are prefixed with the report's index, e.g. "[2].documenturi".
+func (s *reportsService) CreateReports(ctx context.Context, reports []domain.Report) error {
+	if len(reports) == 0 {
+		return nil
+	}
+
+	errs := &domain.ValidationError{}
+	for i := range reports {
+		reports[i].Report.Truncate()
+		var reportErr *domain.ValidationError
+		if errors.As(reports[i].Report.Validate(), &reportErr) {
+			for _, fieldErr := range reportErr.Errors {
+				fieldErr.Field = fmt.Sprintf("[%d].%s", i, fieldErr.Field)
+				errs.Errors = append(errs.Errors, fieldErr)
+			}
+		}
+	}
+	if len(errs.Errors) > 0 {
+		return fmt.Errorf("%w: %w", ErrValidation, errs)
+	}
+
+	for i := range reports {
+		s.classify(&reports[i].Report)
+		reports[i].ID = primitive.NewObjectID()
+	}
+	if err := s.repo.CreateReports(ctx, reports); err != nil {
+		return err
+	}
+	s.recordIssues(ctx, reports)
+	return nil
+}
+
+// GetReport returns the report with the given ID, rejecting malformed IDs before querying the repository
 func (s *reportsService) GetReport(ctx context.Context, id string) (*domain.Report, error) {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return nil, ErrInvalidID
+	}
 	return s.repo.GetReport(ctx, id)
 }
 
-func (s *reportsService) ListReports(ctx context.Context) ([]domain.Report, error) {
-	return s.repo.ListReports(ctx)
+func (s *reportsService) ListReports(ctx context.Context, query ReportQuery) (*ReportPage, error) {
+	query.Normalize()
+	return s.repo.ListReports(ctx, query)
 }
@@ -0,0 +1,113 @@
+package application
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// recordingReportsRepository records the reports passed to it
+type recordingReportsRepository struct {
+	ReportsRepository
+	created []domain.Report
+}
+
+func (r *recordingReportsRepository) CreateReport(ctx context.Context, report *domain.Report) error {
+	r.created = append(r.created, *report)
+	return nil
+}
+
+func (r *recordingReportsRepository) CreateReports(ctx context.Context, reports []domain.Report) error {
+	r.created = append(r.created, reports...)
+	return nil
+}
+
+// validReportData returns report data that passes validation
+func validReportData() domain.ReportData {
+	return domain.ReportData{
+		DocumentUri:        "https://example.com/",
+		EffectiveDirective: "script-src-elem",
+	}
+}
+
+func TestCreateReportAssignsID(t *testing.T) {
+	repo := &recordingReportsRepository{}
+	service := NewReportsService(repo, nil, nil)
+
+	clientID := primitive.NewObjectID()
+	report := domain.Report{ID: clientID, Report: validReportData()}
+	assert.NoError(t, service.CreateReport(context.Background(), &report))
+
+	assert.False(t, report.ID.IsZero())
+	assert.NotEqual(t, clientID, report.ID)
+	assert.Equal(t, report.ID, repo.created[0].ID)
+}
+
+func TestCreateReportsAssignsUniqueIDs(t *testing.T) {
+	repo := &recordingReportsRepository{}
+	service := NewReportsService(repo, nil, nil)
+
+	reports := make([]domain.Report, 3)
+	for i := range reports {
+		reports[i].Report = validReportData()
+	}
+	assert.NoError(t, service.CreateReports(context.Background(), reports))
+
+	seen := map[primitive.ObjectID]bool{}
+	for _, report := range repo.created {
+		assert.False(t, report.ID.IsZero())
+		assert.False(t, seen[report.ID])
+		seen[report.ID] = true
+	}
+	assert.Len(t, seen, 3)
+}
+
+func TestGetReportRejectsInvalidID(t *testing.T) {
+	service := NewReportsService(&recordingReportsRepository{}, nil, nil)
+
+	_, err := service.GetReport(context.Background(), "not-an-id")
+	assert.ErrorIs(t, err, ErrInvalidID)
+}
+
+func TestCreateReportValidates(t *testing.T) {
+	repo := &recordingReportsRepository{}
+	service := NewReportsService(repo, nil, nil)
+
+	report := domain.Report{Report: validReportData()}
+	report.Report.EffectiveDirective = "script-source"
+	report.Report.ScriptSample = strings.Repeat("x", domain.MaxScriptSampleLength+100)
+
+	err := service.CreateReport(context.Background(), &report)
+	assert.ErrorIs(t, err, ErrValidation)
+
+	var validationErr *domain.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []domain.FieldError{{Field: domain.FieldEffectiveDirective, Message: `unknown directive "script-source"`}}, validationErr.Errors)
+	assert.Empty(t, repo.created)
+
+	// Overlong samples are truncated rather than rejected
+	report.Report.EffectiveDirective = "script-src"
+	require.NoError(t, service.CreateReport(context.Background(), &report))
+	assert.Len(t, repo.created[0].Report.ScriptSample, domain.MaxScriptSampleLength)
+}
+
+func TestCreateReportsRejectsBatchWithInvalidReport(t *testing.T) {
+	repo := &recordingReportsRepository{}
+	service := NewReportsService(repo, nil, nil)
+
+	reports := []domain.Report{{Report: validReportData()}, {Report: validReportData()}}
+	reports[1].Report.DocumentUri = "not a url"
+
+	err := service.CreateReports(context.Background(), reports)
+	assert.ErrorIs(t, err, ErrValidation)
+
+	var validationErr *domain.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []domain.FieldError{{Field: "[1].documenturi", Message: "must be an absolute URL"}}, validationErr.Errors)
+	assert.Empty(t, repo.created)
+}
@@ -0,0 +1,670 @@
+// Package repositorytest provides a conformance suite for implementations of
+// application.Repository. Every storage backend runs it from its own tests, so
+// all backends share the semantics the services and handlers rely on.
+package repositorytest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Factory returns an empty repository. It is called once per test and should
+// register any cleanup with t.
+type Factory func(t *testing.T) application.Repository
+
+// Run runs the conformance suite against the repositories returned by newRepository
+func Run(t *testing.T, newRepository Factory) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, repo application.Repository)
+	}{
+		{"CreateAndGetReport", testCreateAndGetReport},
+		{"GetReportNotFound", testGetReportNotFound},
+		{"ListReportsOrder", testListReportsOrder},
+		{"ListReportsPagination", testListReportsPagination},
+		{"ListReportsFilters", testListReportsFilters},
+		{"ListReportsInvalidCursor", testListReportsInvalidCursor},
+		{"TopOrderingAndTieBreaking", testTopOrderingAndTieBreaking},
+		{"TopLimit", testTopLimit},
+		{"TopFilters", testTopFilters},
+		{"TopEmpty", testTopEmpty},
+		{"TopBlockedURIOrigins", testTopBlockedURIOrigins},
+		{"TopSourceFileOrigins", testTopSourceFileOrigins},
+		{"TimeSeriesCounts", testTimeSeriesCounts},
+		{"NoiseFilter", testNoiseFilter},
+		{"RecordAndGetIssues", testRecordAndGetIssues},
+		{"ListIssues", testListIssues},
+		{"IssueLifecycle", testIssueLifecycle},
+		{"IssueRegressesInResolutionSecond", testIssueRegressesInResolutionSecond},
+		{"SetIssueStatusNotFound", testSetIssueStatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newRepository(t))
+		})
+	}
+}
+
+// seed stores a report with a new ID for each data and returns the stored reports
+func seed(t *testing.T, repo application.Repository, data ...domain.ReportData) []domain.Report {
+	reports := make([]domain.Report, len(data))
+	for i := range data {
+		reports[i] = domain.Report{ID: primitive.NewObjectID(), Report: data[i]}
+	}
+	require.NoError(t, repo.CreateReports(context.Background(), reports))
+	return reports
+}
+
+// ids returns the hex IDs of reports in order
+func ids(reports []domain.Report) []string {
+	result := make([]string, len(reports))
+	for i, report := range reports {
+		result[i] = report.ID.Hex()
+	}
+	return result
+}
+
+func testCreateAndGetReport(t *testing.T, repo application.Repository) {
+	ctx := context.Background()
+
+	report := &domain.Report{ID: primitive.NewObjectID(), Report: domain.ReportData{
+		DocumentUri:        "https://example.com/page?q=1",
+		Referrer:           "https://example.com/",
+		ViolatedDirective:  "script-src-elem",
+		EffectiveDirective: "script-src-elem",
+		OriginalPolicy:     "default-src 'self'; report-uri /csp",
+		Disposition:        domain.DispositionEnforce,
+		BlockedUri:         "https://cdn.example.net/app.js",
+		LineNumber:         42,
+		SourceFile:         "https://example.com/main.js",
+		StatusCode:         200,
+		ScriptSample:       "alert(1)",
+		ClientIP:           "192.0.2.1",
+		UserAgent:          "Mozilla/5.0",
+		ReportTime:         1700000000,
+		NoiseCategory:      domain.NoiseInjected,
+		NoiseReason:        "blocked URI belongs to a known script injector",
+		Fingerprint:        "0123456789abcdef0123456789abcdef",
+	}}
+	require.NoError(t, repo.CreateReport(ctx, report))
+
+	batch := seed(t, repo, domain.ReportData{DocumentUri: "https://example.com/a"}, domain.ReportData{DocumentUri: "https://example.com/b"})
+
+	for _, want := range append([]domain.Report{*report}, batch...) {
+		found, err := repo.GetReport(ctx, want.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, want, *found)
+	}
+}
+
+func testGetReportNotFound(t *testing.T, repo application.Repository) {
+	ctx := context.Background()
+	seed(t, repo, domain.ReportData{})
+
+	_, err := repo.GetReport(ctx, primitive.NewObjectID().Hex())
+	assert.ErrorIs(t, err, application.ErrNotFound)
+
+	_, err = repo.GetReport(ctx, "not-an-id")
+	assert.ErrorIs(t, err, application.ErrInvalidID)
+}
+
+func testListReportsOrder(t *testing.T, repo application.Repository) {
+	ctx := context.Background()
+
+	// Reports sharing a report time are ordered by ID, i.e. creation order
+	reports := seed(t, repo,
+		domain.ReportData{ReportTime: 200},
+		domain.ReportData{ReportTime: 100},
+		domain.ReportData{ReportTime: 200},
+		domain.ReportData{ReportTime: 300},
+	)
+
+	newest, err := repo.ListReports(ctx, application.ReportQuery{Limit: 10, Sort: application.SortNewest})
+	require.NoError(t, err)
+	assert.Equal(t, ids([]domain.Report{reports[3], reports[2], reports[0], reports[1]}), ids(newest.Reports))
+	assert.Nil(t, newest.Next)
+	assert.Nil(t, newest.Total, "the total is only counted when requested")
+
+	oldest, err := repo.ListReports(ctx, application.ReportQuery{Limit: 10, Sort: application.SortOldest})
+	require.NoError(t, err)
+	assert.Equal(t, ids([]domain.Report{reports[1], reports[0], reports[2], reports[3]}), ids(oldest.Reports))
+}
+
+func testListReportsPagination(t *testing.T, repo application.Repository) {
+	ctx := context.Background()
+
+	data := make([]domain.ReportData, 7)
+	for i := range data {
+		data[i] = domain.ReportData{ReportTime: 1000 + i/2}
+	}
+	reports := seed(t, repo, data...)
+
+	for _, sort := range []application.SortOrder{application.SortNewest, application.SortOldest} {
+		t.Run(string(sort), func(t *testing.T) {
+			query := application.ReportQuery{Limit: 3, Sort: sort, IncludeTotal: true}
+
+			var listed []domain.Report
+			for pages := 0; ; pages++ {
+				require.Less(t, pages, 3, "pagination does not terminate")
+
+				page, err := repo.ListReports(ctx, query)
+				require.NoError(t, err)
+				require.NotNil(t, page.Total)
+				assert.Equal(t, int64(7), *page.Total)
+				assert.LessOrEqual(t, len(page.Reports), 3)
+
+				listed = append(listed, page.Reports...)
+				if page.Next == nil {
+					break
+				}
+				assert.Equal(t, application.NewReportCursor(page.Reports[len(page.Reports)-1]), page.Next)
+				query.After = page.Next
+			}
+
+			assert.ElementsMatch(t, ids(reports), ids(listed))
+		})
+	}
+
+	// A page that exactly exhausts the reports has no next cursor
+	page, err := repo.ListReports(ctx, application.ReportQuery{Limit: 7})
+	require.NoError(t, err)
+	assert.Len(t, page.Reports, 7)
+	assert.Nil(t, page.Next)
+}
+
+func testListReportsFilters(t *testing.T, repo application.Repository) {
+	ctx := context.Background()
+
+	seed(t, repo,
+		domain.ReportData{DocumentUri: "https://example.com/a", EffectiveDirective: "script-src", Disposition: "enforce", ReportTime: 100},
+		domain.ReportData{DocumentUri: "https://example.com/b", EffectiveDirective: "style-src", Disposition: "report", ReportTime: 200},
+		domain.ReportData{DocumentUri: "https://example.org/x.y", BlockedUri: "https://cdn.example.net/*", ReportTime: 200},
+		domain.ReportData{DocumentUri: "https://exampleXcom/", BlockedUri: "https://cdn.example.net/100%_off", ReportTime: 300},
+	)
+
+	tests := []struct {
+		name   string
+		filter application.ReportFilter
+		want   int
+	}{
+		{"none", application.ReportFilter{}, 4},
+		{"exact", application.ReportFilter{DocumentUri: &application.StringFilter{Value: "https://example.com/a"}}, 1},
+		{"exact is not a prefix", application.ReportFilter{DocumentUri: &application.StringFilter{Value: "https://example.com"}}, 0},
+		{"prefix", application.ReportFilter{DocumentUri: &application.StringFilter{Value: "https://example.com/", Match: application.MatchPrefix}}, 2},
+		{"prefix is literal", application.ReportFilter{DocumentUri: &application.StringFilter{Value: "https://example.", Match: application.MatchPrefix}}, 3},
+		{"prefix is case sensitive", application.ReportFilter{DocumentUri: &application.StringFilter{Value: "HTTPS://", Match: application.MatchPrefix}}, 0},
+		{"contains", application.ReportFilter{DocumentUri: &application.StringFilter{Value: "example.co", Match: application.MatchContains}}, 2},
+		{"contains is literal", application.ReportFilter{BlockedUri: &application.StringFilter{Value: "/*", Match: application.MatchContains}}, 1},
+		{"contains with SQL wildcards", application.ReportFilter{BlockedUri: &application.StringFilter{Value: "%_", Match: application.MatchContains}}, 1},
+		{"combined", application.ReportFilter{
+			EffectiveDirective: &application.StringFilter{Value: "s", Match: application.MatchPrefix},
+			Disposition:        &application.StringFilter{Value: "report"},
+		}, 1},
+		{"from is inclusive", application.ReportFilter{From: 200}, 3},
+		{"to is exclusive", application.ReportFilter{To: 200}, 1},
+		{"range", application.ReportFilter{From: 200, To: 300}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := repo.ListReports(ctx, application.ReportQuery{Filter: tt.filter, Limit: 10, IncludeTotal: true})
+			require.NoError(t, err)
+			assert.Len(t, page.Reports, tt.want)
+			assert.Equal(t, int64(tt.want), *page.Total)
+		})
+	}
+}
+
+func testListReportsInvalidCursor(t *testing.T, repo application.Repository) {
+	seed(t, repo, domain.ReportData{})
+
+	_, err := repo.ListReports(context.Background(), application.ReportQuery{
+		Limit: 10,
+		After: &application.ReportCursor{ReportTime: 1, ID: "not-an-id"},
+	})
+	assert.ErrorIs(t, err, application.ErrInvalidCursor)
+}
+
+func testTopOrderingAndTieBreaking(t *testing.T, repo application.Repository) {
+	ctx := context.Background()
+
+	// Counts: 10.0.0.3 x3, then a two-way tie, then a three-way tie ordered by
+	// byte value, so upper case sorts before lower case
+	var data []domain.ReportData
+	for ip, count := range map[string]int{"10.0.0.3": 3, "10.0.0.2": 2, "10.0.0.10": 2, "b": 1, "B": 1, "a": 1} {
+		for i := 0; i < count; i++ {
+			data = append(data, domain.ReportData{ClientIP: ip})
+		}
+	}
+	seed(t, repo, data...)
+
+	results, err := repo.GetTopIPs(ctx, application.StatisticsParams{Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []application.TopIPResult{
+		{IP: "10.0.0.3", Count: 3},
+		{IP: "10.0.0.10", Count: 2},
+		{IP: "10.0.0.2", Count: 2},
+		{IP: "B", Count: 1},
+		{IP: "a", Count: 1},
+		{IP: "b", Count: 1},
+	}, results)
+}
+
+func testTopLimit(t *testing.T, repo application.Repository) {
+	ctx := context.Background()
+
+	var data []domain.ReportData
+	for i := 0; i < 5; i++ {
+		for j := 0; j <= i; j++ {
+			data = append(data, domain.ReportData{
+				ViolatedDirective:  fmt.Sprintf("directive-%d", i),
+				EffectiveDirective: fmt.Sprintf("directive-%d", i),
+				DocumentUri:        fmt.Sprintf("https://example.com/%d", i),
+				SourceFile:         fmt.Sprintf("https://example.com/%d.js", i),
+				BlockedUri:         fmt.Sprintf("https://cdn%d.example.net/", i),
+			})
+		}
+	}
+	seed(t, repo, data...)
+
+	params := application.StatisticsParams{Limit: 2}
+
+	violated, err := repo.GetTopViolatedDirectives(ctx, params)
+	require.NoError(t, err)
+	assert.Equal(t, []application.TopDirectiveResult{{Directive: "directive-4", Count: 5}, {Directive: "directive-3", Count: 4}}, violated)
+
+	effective, err := repo.GetTopEffectiveDirectives(ctx, params)
+	require.NoError(t, err)
+	assert.Equal(t, violated, effective)
+
+	documents, err := repo.GetTopDocuments(ctx, params)
+	require.NoError(t, err)
+	assert.Equal(t, []application.TopDocumentResult{{DocumentURI: "https://example.com/4", Count: 5}, {DocumentURI: "https://example.com/3", Count: 4}}, documents)
+
+	sourceFiles, err := repo.GetTopSourceFiles(ctx, params)
+	require.NoError(t, err)
+	assert.Equal(t, []application.TopSourceFileResult{{SourceFile: "https://example.com/4.js", Count: 5}, {SourceFile: "https://example.com/3.js", Count: 4}}, sourceFiles)
+
+	blocked, err := repo.GetTopBlockedURIs(ctx, params)
+	require.NoError(t, err)
+	assert.Equal(t, []application.TopBlockedURIResult{{BlockedURI: "https://cdn4.example.net/", Count: 5}, {BlockedURI: "https://cdn3.example.net/", Count: 4}}, blocked)
+
+	all, err := repo.GetTopViolatedDirectives(ctx, application.StatisticsParams{Limit: 100})
+	require.NoError(t, err)
+	assert.Len(t, all, 5)
+}
+
+func testTopFilters(t *testing.T, repo application.Repository) {
+	ctx := context.Background()
+
+	seed(t, repo,
+		domain.ReportData{ClientIP: "10.0.0.1", DocumentUri: "https://example.com/a", ReportTime: 100},
+		domain.ReportData{ClientIP: "10.0.0.1", DocumentUri: "https://example.com/b", ReportTime: 200},
+		domain.ReportData{ClientIP: "10.0.0.2", DocumentUri: "https://example.com/b", ReportTime: 200},
+		domain.ReportData{ClientIP: "10.0.0.2", DocumentUri: "https://example.org/", ReportTime: 300},
+	)
+
+	results, err := repo.GetTopIPs(ctx, application.StatisticsParams{
+		Limit: 10,
+		Filter: application.ReportFilter{
+			DocumentUri: &application.StringFilter{Value: "https://example.com/", Match: application.MatchPrefix},
+			From:        200,
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []application.TopIPResult{{IP: "10.0.0.1", Count: 1}, {IP: "10.0.0.2", Count: 1}}, results)
+}
+
+func testTopEmpty(t *testing.T, repo application.Repository) {
+	results, err := repo.GetTopIPs(context.Background(), application.StatisticsParams{Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func testTopBlockedURIOrigins(t *testing.T, repo application.Repository) {
+	ctx := context.Background()
+
+	seed(t, repo,
+		domain.ReportData{BlockedUri: "https://cdn.example.net/a.js"},
+		domain.ReportData{BlockedUri: "https://cdn.example.net:8443/b.js?v=1"},
+		domain.ReportData{BlockedUri: "https://cdn.example.net#frag"},
+		domain.ReportData{BlockedUri: "inline"},
+		domain.ReportData{BlockedUri: "inline"},
+		domain.ReportData{BlockedUri: "data"},
+	)
+
+	results, err := repo.GetTopBlockedURIs(ctx, application.StatisticsParams{Limit: 10, NormalizeOrigin: true})
+	require.NoError(t, err)
+	assert.Equal(t, []application.TopBlockedURIResult{
+		{BlockedURI: "https://cdn.example.net", Count: 2},
+		{BlockedURI: "inline", Count: 2},
+		{BlockedURI: "data", Count: 1},
+		{BlockedURI: "https://cdn.example.net:8443", Count: 1},
+	}, results)
+}
+
+func testTopSourceFileOrigins(t *testing.T, repo application.Repository) {
+	ctx := context.Background()
+
+	seed(t, repo,
+		domain.ReportData{SourceFile: "https://cdn.example.net/a.js"},
+		domain.ReportData{SourceFile: "https://cdn.example.net/b.js"},
+		domain.ReportData{SourceFile: "moz-extension://abc/content.js"},
+		domain.ReportData{SourceFile: "inline"},
+	)
+
+	results, err := repo.GetTopSourceFiles(ctx, application.StatisticsParams{Limit: 10, NormalizeOrigin: true})
+	require.NoError(t, err)
+	assert.Equal(t, []application.TopSourceFileResult{
+		{SourceFile: "https://cdn.example.net", Count: 2},
+		{SourceFile: "inline", Count: 1},
+		{SourceFile: "moz-extension://abc", Count: 1},
+	}, results)
+}
+
+func testTimeSeriesCounts(t *testing.T, repo application.Repository) {
+	ctx := context.Background()
+
+	seed(t, repo,
+		domain.ReportData{EffectiveDirective: "script-src", ReportTime: 1700000010},
+		domain.ReportData{EffectiveDirective: "script-src", ReportTime: 1700000030},
+		domain.ReportData{EffectiveDirective: "img-src", ReportTime: 1700000039},
+		domain.ReportData{EffectiveDirective: "img-src", ReportTime: 1700000130},
+		domain.ReportData{EffectiveDirective: "img-src", ReportTime: 1700009999},
+	)
+
+	filter := application.ReportFilter{From: 1699999980, To: 1700000160}
+
+	counts, err := repo.GetTimeSeriesCounts(ctx, application.TimeSeriesParams{Bucket: application.BucketMinute, Filter: filter})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []application.TimeSeriesCount{
+		{Bucket: 1699999980, Count: 3},
+		{Bucket: 1700000100, Count: 1},
+	}, counts)
+
+	grouped, err := repo.GetTimeSeriesCounts(ctx, application.TimeSeriesParams{
+		Bucket:  application.BucketMinute,
+		GroupBy: domain.FieldEffectiveDirective,
+		Filter:  filter,
+	})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []application.TimeSeriesCount{
+		{Bucket: 1699999980, Group: "script-src", Count: 2},
+		{Bucket: 1699999980, Group: "img-src", Count: 1},
+		{Bucket: 1700000100, Group: "img-src", Count: 1},
+	}, grouped)
+
+	_, err = repo.GetTimeSeriesCounts(ctx, application.TimeSeriesParams{
+		Bucket: application.BucketMinute, GroupBy: "clientip) FROM reports; --", Filter: filter,
+	})
+	assert.ErrorIs(t, err, application.ErrInvalidGroupBy)
+}
+
+func testNoiseFilter(t *testing.T, repo application.Repository) {
+	ctx := context.Background()
+
+	seed(t, repo,
+		domain.ReportData{EffectiveDirective: "script-src", ReportTime: 100},
+		domain.ReportData{EffectiveDirective: "script-src", ReportTime: 100, NoiseCategory: domain.NoiseExtension, NoiseReason: "extension"},
+		domain.ReportData{EffectiveDirective: "img-src", ReportTime: 100, NoiseCategory: domain.NoiseBrowser, NoiseReason: "about:blank"},
+	)
+
+	tests := []struct {
+		name   string
+		filter application.ReportFilter
+		want   int
+	}{
+		{"unset", application.ReportFilter{}, 3},
+		{"include", application.ReportFilter{Noise: application.NoiseInclude}, 3},
+		{"exclude", application.ReportFilter{Noise: application.NoiseExclude}, 1},
+		{"only", application.ReportFilter{Noise: application.NoiseOnly}, 2},
+		{"category", application.ReportFilter{NoiseCategory: &application.StringFilter{Value: domain.NoiseBrowser}}, 1},
+		{"only in category", application.ReportFilter{Noise: application.NoiseOnly, NoiseCategory: &application.StringFilter{Value: domain.NoiseBrowser}}, 1},
+		{"exclude with category", application.ReportFilter{Noise: application.NoiseExclude, NoiseCategory: &application.StringFilter{Value: domain.NoiseBrowser}}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := repo.ListReports(ctx, application.ReportQuery{Filter: tt.filter, Limit: 10, IncludeTotal: true})
+			require.NoError(t, err)
+			assert.Len(t, page.Reports, tt.want)
+			assert.Equal(t, int64(tt.want), *page.Total)
+		})
+	}
+
+	directives, err := repo.GetTopEffectiveDirectives(ctx, application.StatisticsParams{
+		Limit:  10,
+		Filter: application.ReportFilter{Noise: application.NoiseExclude},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []application.TopDirectiveResult{{Directive: "script-src", Count: 1}}, directives)
+
+	counts, err := repo.GetTimeSeriesCounts(ctx, application.TimeSeriesParams{
+		Bucket: application.BucketMinute,
+		Filter: application.ReportFilter{From: 60, To: 180, Noise: application.NoiseOnly},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []application.TimeSeriesCount{{Bucket: 60, Count: 2}}, counts)
+}
+
+// issue returns an issue with a count of one seen at reportTime
+func issue(fingerprint string, reportTime int) domain.Issue {
+	return domain.Issue{
+		Fingerprint:        fingerprint,
+		DocumentPath:       "example.com/checkout",
+		EffectiveDirective: "script-src-elem",
+		BlockedOrigin:      "https://cdn.example.net",
+		SourceFile:         "https://example.com/app.js",
+		FirstSeen:          reportTime,
+		LastSeen:           reportTime,
+		Count:              1,
+		Status:             domain.IssueOpen,
+	}
+}
+
+// record records issues one at a time and returns the fingerprints that regressed
+func record(t *testing.T, repo application.Repository, issues ...domain.Issue) []string {
+	var regressed []string
+	for _, i := range issues {
+		fingerprints, err := repo.RecordIssues(context.Background(), []domain.Issue{i})
+		require.NoError(t, err)
+		regressed = append(regressed, fingerprints...)
+	}
+	return regressed
+}
+
+func testRecordAndGetIssues(t *testing.T, repo application.Repository) {
+	ctx := context.Background()
+	const fingerprint = "00000000000000000000000000000001"
+
+	_, err := repo.GetIssue(ctx, fingerprint)
+	assert.ErrorIs(t, err, application.ErrNotFound)
+
+	first := issue(fingerprint, 200)
+	first.Count = 2
+	record(t, repo, first)
+	regressed, err := repo.RecordIssues(ctx, []domain.Issue{issue(fingerprint, 100), issue(fingerprint, 300)})
+	require.NoError(t, err)
+	assert.Empty(t, regressed)
+
+	found, err := repo.GetIssue(ctx, fingerprint)
+	require.NoError(t, err)
+	want := issue(fingerprint, 0)
+	want.FirstSeen, want.LastSeen, want.Count = 100, 300, 4
+	assert.Equal(t, want, *found)
+
+	// Reports are drilled down into by their fingerprint
+	reports := seed(t, repo,
+		domain.ReportData{ReportTime: 100, Fingerprint: fingerprint},
+		domain.ReportData{ReportTime: 200, Fingerprint: "00000000000000000000000000000002"},
+	)
+	page, err := repo.ListReports(ctx, application.ReportQuery{
+		Filter: application.ReportFilter{Fingerprint: &application.StringFilter{Value: fingerprint, Match: application.MatchExact}},
+		Limit:  10,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ids(reports[:1]), ids(page.Reports))
+}
+
+func testListIssues(t *testing.T, repo application.Repository) {
+	ctx := context.Background()
+
+	// a: 3 reports, last seen 100; b: 1 report, last seen 300; c: 3 reports, last seen 200
+	a, b, c := "0000000000000000000000000000000a", "0000000000000000000000000000000b", "0000000000000000000000000000000c"
+	var issues []domain.Issue
+	for _, fingerprint := range []string{a, a, a, c, c} {
+		issues = append(issues, issue(fingerprint, 100))
+	}
+	issues = append(issues, issue(b, 300), issue(c, 200))
+	record(t, repo, issues...)
+
+	fingerprints := func(page *application.IssuePage) []string {
+		result := []string{}
+		for _, issue := range page.Issues {
+			result = append(result, issue.Fingerprint)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name  string
+		query application.IssueQuery
+		// pages lists the fingerprints of each page, following the cursors
+		pages [][]string
+	}{
+		// Ties in count are broken by fingerprint
+		{"count", application.IssueQuery{Sort: application.IssueSortCount, Limit: 10}, [][]string{{a, c, b}}},
+		{"recent", application.IssueQuery{Sort: application.IssueSortRecent, Limit: 10}, [][]string{{b, c, a}}},
+		{"count pages", application.IssueQuery{Sort: application.IssueSortCount, Limit: 2}, [][]string{{a, c}, {b}}},
+		{"recent pages", application.IssueQuery{Sort: application.IssueSortRecent, Limit: 1}, [][]string{{b}, {c}, {a}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query.Statuses = application.ActiveIssueStatuses
+			tt.query.IncludeTotal = true
+			for i, want := range tt.pages {
+				page, err := repo.ListIssues(ctx, tt.query)
+				require.NoError(t, err)
+				assert.Equal(t, want, fingerprints(page))
+				assert.Equal(t, int64(3), *page.Total)
+				if i == len(tt.pages)-1 {
+					assert.Nil(t, page.Next)
+					break
+				}
+				require.NotNil(t, page.Next)
+				tt.query.After = page.Next
+			}
+		})
+	}
+
+	// Issues are selected by status
+	_, err := repo.SetIssueStatus(ctx, a, domain.IssueStatusChange{Status: domain.IssueIgnored, Actor: "alice", Time: 400})
+	require.NoError(t, err)
+	page, err := repo.ListIssues(ctx, application.IssueQuery{
+		Statuses: application.ActiveIssueStatuses, Sort: application.IssueSortCount, Limit: 10, IncludeTotal: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{c, b}, fingerprints(page))
+	assert.Equal(t, int64(2), *page.Total)
+
+	page, err = repo.ListIssues(ctx, application.IssueQuery{
+		Statuses: []domain.IssueStatus{domain.IssueIgnored}, Sort: application.IssueSortCount, Limit: 10,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{a}, fingerprints(page))
+
+	// An issue recorded between pages that sorts before the cursor doesn't
+	// shift the following pages
+	query := application.IssueQuery{Statuses: application.ActiveIssueStatuses, Sort: application.IssueSortCount, Limit: 1}
+	page, err = repo.ListIssues(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, []string{c}, fingerprints(page))
+	d := "0000000000000000000000000000000d"
+	record(t, repo, issue(d, 500), issue(d, 500), issue(d, 500), issue(d, 500))
+	query.After = page.Next
+	page, err = repo.ListIssues(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, []string{b}, fingerprints(page))
+	assert.Nil(t, page.Next)
+}
+
+func testIssueLifecycle(t *testing.T, repo application.Repository) {
+	ctx := context.Background()
+	resolved, ignored, snoozed := "0000000000000000000000000000000a", "0000000000000000000000000000000b", "0000000000000000000000000000000c"
+	record(t, repo, issue(resolved, 100), issue(ignored, 100), issue(snoozed, 100))
+
+	updated, err := repo.SetIssueStatus(ctx, resolved, domain.IssueStatusChange{
+		Status: domain.IssueResolved, Note: "$ allowed in the policy", Actor: "alice", Time: 200,
+	})
+	require.NoError(t, err)
+	want := issue(resolved, 100)
+	want.Status, want.StatusNote, want.StatusActor, want.StatusTime = domain.IssueResolved, "$ allowed in the policy", "alice", 200
+	assert.Equal(t, want, *updated)
+
+	_, err = repo.SetIssueStatus(ctx, ignored, domain.IssueStatusChange{Status: domain.IssueIgnored, Actor: "bob", Time: 200})
+	require.NoError(t, err)
+	updated, err = repo.SetIssueStatus(ctx, snoozed, domain.IssueStatusChange{Status: domain.IssueSnoozed, SnoozeCount: 2, Actor: "bob", Time: 200})
+	require.NoError(t, err)
+	assert.Equal(t, 3, updated.SnoozeUntil)
+
+	// Reports from before the resolution arriving late don't reopen the issue
+	assert.Empty(t, record(t, repo, issue(resolved, 150), issue(ignored, 300), issue(snoozed, 300)))
+	found, err := repo.GetIssue(ctx, resolved)
+	require.NoError(t, err)
+	assert.Equal(t, domain.IssueResolved, found.Status)
+	found, err = repo.GetIssue(ctx, snoozed)
+	require.NoError(t, err)
+	assert.Equal(t, domain.IssueSnoozed, found.Status)
+
+	assert.Equal(t, []string{resolved}, record(t, repo, issue(resolved, 300), issue(ignored, 400), issue(snoozed, 400)))
+	// A regressed issue is reported once
+	assert.Empty(t, record(t, repo, issue(resolved, 500)))
+
+	found, err = repo.GetIssue(ctx, resolved)
+	require.NoError(t, err)
+	assert.Equal(t, domain.IssueRegressed, found.Status)
+	assert.Equal(t, 300, found.RegressedAt)
+	assert.Equal(t, 4, found.Count)
+	assert.Equal(t, "alice", found.StatusActor)
+
+	found, err = repo.GetIssue(ctx, ignored)
+	require.NoError(t, err)
+	assert.Equal(t, domain.IssueIgnored, found.Status)
+	assert.Equal(t, 3, found.Count)
+
+	found, err = repo.GetIssue(ctx, snoozed)
+	require.NoError(t, err)
+	assert.Equal(t, domain.IssueOpen, found.Status)
+	assert.Equal(t, 0, found.SnoozeUntil)
+}
+
+func testIssueRegressesInResolutionSecond(t *testing.T, repo application.Repository) {
+	ctx := context.Background()
+	fingerprint := "0000000000000000000000000000000a"
+	record(t, repo, issue(fingerprint, 100))
+	_, err := repo.SetIssueStatus(ctx, fingerprint, domain.IssueStatusChange{Status: domain.IssueResolved, Actor: "alice", Time: 200})
+	require.NoError(t, err)
+
+	// Report times are whole seconds, so a report in the second of the
+	// resolution may have been sent after it
+	assert.Equal(t, []string{fingerprint}, record(t, repo, issue(fingerprint, 200)))
+	found, err := repo.GetIssue(ctx, fingerprint)
+	require.NoError(t, err)
+	assert.Equal(t, domain.IssueRegressed, found.Status)
+	assert.Equal(t, 200, found.RegressedAt)
+}
+
+func testSetIssueStatusNotFound(t *testing.T, repo application.Repository) {
+	_, err := repo.SetIssueStatus(context.Background(), "00000000000000000000000000000001",
+		domain.IssueStatusChange{Status: domain.IssueResolved, Actor: "alice", Time: 100})
+	assert.ErrorIs(t, err, application.ErrNotFound)
+}
@@ -0,0 +1,119 @@
+package application_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/infrastructure/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// These tests exercise the services end to end against the in-memory repository
+
+func TestServiceReportsRoundTrip(t *testing.T) {
+	service := application.NewService(memory.NewMemoryRepository(), domain.DefaultNoiseClassifier(), nil)
+	ctx := context.Background()
+
+	reports := make([]domain.Report, 150)
+	for i := range reports {
+		reports[i].Report = domain.ReportData{
+			DocumentUri:        "https://example.com/",
+			EffectiveDirective: "img-src",
+			ReportTime:         1700000000 + i,
+		}
+	}
+	require.NoError(t, service.Reports.CreateReports(ctx, reports))
+
+	found, err := service.Reports.GetReport(ctx, reports[7].ID.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, reports[7], *found)
+
+	// An unset limit falls back to the default page size
+	query := application.ReportQuery{IncludeTotal: true}
+	first, err := service.Reports.ListReports(ctx, query)
+	require.NoError(t, err)
+	assert.Len(t, first.Reports, application.DefaultReportsLimit)
+	assert.Equal(t, int64(150), *first.Total)
+	assert.Equal(t, 1700000149, first.Reports[0].Report.ReportTime)
+	require.NotNil(t, first.Next)
+
+	query.After = first.Next
+	second, err := service.Reports.ListReports(ctx, query)
+	require.NoError(t, err)
+	assert.Len(t, second.Reports, 50)
+	assert.Nil(t, second.Next)
+	assert.Equal(t, 1700000000, second.Reports[49].Report.ReportTime)
+}
+
+func TestServiceStatistics(t *testing.T) {
+	service := application.NewService(memory.NewMemoryRepository(), domain.DefaultNoiseClassifier(), nil)
+	ctx := context.Background()
+
+	var reports []domain.Report
+	for i := 0; i < 30; i++ {
+		reports = append(reports, domain.Report{Report: domain.ReportData{
+			DocumentUri:        "https://example.com/",
+			ClientIP:           fmt.Sprintf("10.0.0.%d", i%26),
+			EffectiveDirective: "script-src",
+			ReportTime:         1700000000 + 60*(i%3),
+		}})
+	}
+	require.NoError(t, service.Reports.CreateReports(ctx, reports))
+
+	// An unset limit falls back to the statistic's default
+	ips, err := service.Statistics.GetTopIPs(ctx, application.StatisticsParams{})
+	require.NoError(t, err)
+	assert.Len(t, ips, application.DefaultTopIPsLimit)
+	assert.Equal(t, 2, ips[0].Count)
+
+	timeSeries, err := service.Statistics.GetTimeSeries(ctx, application.TimeSeriesParams{
+		Bucket: application.BucketMinute,
+		Filter: application.ReportFilter{From: 1699999980, To: 1700000280},
+	})
+	require.NoError(t, err)
+	require.Len(t, timeSeries.Series, 1)
+
+	var counts []int
+	for _, point := range timeSeries.Series[0].Points {
+		counts = append(counts, point.Count)
+	}
+	assert.Equal(t, []int{10, 10, 10, 0, 0}, counts)
+
+	// A range starting in the future ends before it starts
+	_, err = service.Statistics.GetTimeSeries(ctx, application.TimeSeriesParams{
+		Filter: application.ReportFilter{From: int(time.Now().Add(48 * time.Hour).Unix())},
+	})
+	assert.ErrorIs(t, err, application.ErrInvalidRange)
+}
+
+func TestServiceStatisticsNoise(t *testing.T) {
+	service := application.NewService(memory.NewMemoryRepository(), domain.DefaultNoiseClassifier(), nil)
+	ctx := context.Background()
+
+	require.NoError(t, service.Reports.CreateReports(ctx, []domain.Report{
+		{Report: domain.ReportData{DocumentUri: "https://example.com/", EffectiveDirective: "script-src-elem", BlockedUri: "https://cdn.example.net/app.js", ReportTime: 1700000000}},
+		{Report: domain.ReportData{DocumentUri: "https://example.com/", EffectiveDirective: "script-src-elem", BlockedUri: "chrome-extension://abcdef/inject.js", ReportTime: 1700000000}},
+	}))
+
+	blockedURIs := func(filter application.ReportFilter) []string {
+		results, err := service.Statistics.GetTopBlockedURIs(ctx, application.StatisticsParams{Filter: filter})
+		require.NoError(t, err)
+		var uris []string
+		for _, result := range results {
+			uris = append(uris, result.BlockedURI)
+		}
+		return uris
+	}
+
+	assert.Equal(t, []string{"https://cdn.example.net/app.js"}, blockedURIs(application.ReportFilter{}))
+	assert.Equal(t, []string{"chrome-extension://abcdef/inject.js"}, blockedURIs(application.ReportFilter{Noise: application.NoiseOnly}))
+	// Filtering by noise category selects noise without noise=only
+	assert.Equal(t, []string{"chrome-extension://abcdef/inject.js"}, blockedURIs(application.ReportFilter{
+		NoiseCategory: &application.StringFilter{Value: domain.NoiseExtension, Match: application.MatchExact},
+	}))
+}
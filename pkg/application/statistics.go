@@ -16,16 +16,78 @@ type TopDirectiveResult struct {
 	Count     int    `json:"count"`
 }
 
+// TopBlockedURIResult represents a blocked URI, or its origin, with its occurrence count
+type TopBlockedURIResult struct {
+	BlockedURI string `json:"blockeduri"`
+	Count      int    `json:"count"`
+}
+
+// TopDocumentResult represents a document URI with its occurrence count
+type TopDocumentResult struct {
+	DocumentURI string `json:"documenturi"`
+	Count       int    `json:"count"`
+}
+
+// TopSourceFileResult represents a source file with its occurrence count
+type TopSourceFileResult struct {
+	SourceFile string `json:"sourcefile"`
+	Count      int    `json:"count"`
+}
+
+const (
+	// DefaultTopIPsLimit is the number of client IPs returned when no limit is set
+	DefaultTopIPsLimit = 20
+	// DefaultTopDirectivesLimit is the number of directives returned when no limit is set
+	DefaultTopDirectivesLimit = 10
+	// DefaultTopURIsLimit is the number of blocked URIs, documents or source files returned when no limit is set
+	DefaultTopURIsLimit = 20
+	// MaxStatisticsLimit is the largest number of entries a statistics query may request
+	MaxStatisticsLimit = 1000
+)
+
+// StatisticsParams defines the scope of a statistics query
+type StatisticsParams struct {
+	Limit  int
+	Filter ReportFilter
+	// NormalizeOrigin groups blocked URIs and source files by their origin, e.g.
+	// https://cdn.example.net, instead of the full URI. Values without an origin such as "inline" are kept as is.
+	NormalizeOrigin bool
+}
+
+// withDefaults applies the given default limit, caps the limit and excludes
+// noise unless the filter selects it
+func (p StatisticsParams) withDefaults(limit int) StatisticsParams {
+	p.Filter = p.Filter.excludingNoiseByDefault()
+	if p.Limit <= 0 {
+		p.Limit = limit
+	}
+	if p.Limit > MaxStatisticsLimit {
+		p.Limit = MaxStatisticsLimit
+	}
+	return p
+}
+
 // StatisticsRepository defines statistics-specific repository methods
 type StatisticsRepository interface {
-	GetTopIPs(ctx context.Context) ([]TopIPResult, error)
-	GetTopViolatedDirectives(ctx context.Context) ([]TopDirectiveResult, error)
+	GetTopIPs(ctx context.Context, params StatisticsParams) ([]TopIPResult, error)
+	GetTopViolatedDirectives(ctx context.Context, params StatisticsParams) ([]TopDirectiveResult, error)
+	GetTopEffectiveDirectives(ctx context.Context, params StatisticsParams) ([]TopDirectiveResult, error)
+	GetTopBlockedURIs(ctx context.Context, params StatisticsParams) ([]TopBlockedURIResult, error)
+	GetTopDocuments(ctx context.Context, params StatisticsParams) ([]TopDocumentResult, error)
+	GetTopSourceFiles(ctx context.Context, params StatisticsParams) ([]TopSourceFileResult, error)
+	// GetTimeSeriesCounts returns the non-zero report counts per bucket and group
+	GetTimeSeriesCounts(ctx context.Context, params TimeSeriesParams) ([]TimeSeriesCount, error)
 }
 
 // StatisticsService defines statistics-specific service methods
 type StatisticsService interface {
-	GetTopIPs(ctx context.Context) ([]TopIPResult, error)
-	GetTopViolatedDirectives(ctx context.Context) ([]TopDirectiveResult, error)
+	GetTopIPs(ctx context.Context, params StatisticsParams) ([]TopIPResult, error)
+	GetTopViolatedDirectives(ctx context.Context, params StatisticsParams) ([]TopDirectiveResult, error)
+	GetTopEffectiveDirectives(ctx context.Context, params StatisticsParams) ([]TopDirectiveResult, error)
+	GetTopBlockedURIs(ctx context.Context, params StatisticsParams) ([]TopBlockedURIResult, error)
+	GetTopDocuments(ctx context.Context, params StatisticsParams) ([]TopDocumentResult, error)
+	GetTopSourceFiles(ctx context.Context, params StatisticsParams) ([]TopSourceFileResult, error)
+	GetTimeSeries(ctx context.Context, params TimeSeriesParams) (*TimeSeries, error)
 }
 
 type statisticsService struct {
@@ -38,10 +100,26 @@ func NewStatisticsService(repo StatisticsRepository) StatisticsService {
 	}
 }
 
-func (s *statisticsService) GetTopIPs(ctx context.Context) ([]TopIPResult, error) {
-	return s.repo.GetTopIPs(ctx)
+func (s *statisticsService) GetTopIPs(ctx context.Context, params StatisticsParams) ([]TopIPResult, error) {
+	return s.repo.GetTopIPs(ctx, params.withDefaults(DefaultTopIPsLimit))
+}
+
+func (s *statisticsService) GetTopViolatedDirectives(ctx context.Context, params StatisticsParams) ([]TopDirectiveResult, error) {
+	return s.repo.GetTopViolatedDirectives(ctx, params.withDefaults(DefaultTopDirectivesLimit))
+}
+
+func (s *statisticsService) GetTopEffectiveDirectives(ctx context.Context, params StatisticsParams) ([]TopDirectiveResult, error) {
+	return s.repo.GetTopEffectiveDirectives(ctx, params.withDefaults(DefaultTopDirectivesLimit))
+}
+
+func (s *statisticsService) GetTopBlockedURIs(ctx context.Context, params StatisticsParams) ([]TopBlockedURIResult, error) {
+	return s.repo.GetTopBlockedURIs(ctx, params.withDefaults(DefaultTopURIsLimit))
+}
+
+func (s *statisticsService) GetTopDocuments(ctx context.Context, params StatisticsParams) ([]TopDocumentResult, error) {
+	return s.repo.GetTopDocuments(ctx, params.withDefaults(DefaultTopURIsLimit))
 }
 
-func (s *statisticsService) GetTopViolatedDirectives(ctx context.Context) ([]TopDirectiveResult, error) {
-	return s.repo.GetTopViolatedDirectives(ctx)
+func (s *statisticsService) GetTopSourceFiles(ctx context.Context, params StatisticsParams) ([]TopSourceFileResult, error) {
+	return s.repo.GetTopSourceFiles(ctx, params.withDefaults(DefaultTopURIsLimit))
 }
@@ -0,0 +1,209 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+)
+
+// MaxTimeSeriesBuckets is the largest number of buckets a time series may span
+const MaxTimeSeriesBuckets = 1500
+
+// ErrTooManyBuckets is returned when a time series spans more than MaxTimeSeriesBuckets buckets
+var ErrTooManyBuckets = fmt.Errorf("time range spans more than %d buckets", MaxTimeSeriesBuckets)
+
+// ErrInvalidBucket is returned for an unknown bucket size
+var ErrInvalidBucket = errors.New("invalid bucket size")
+
+// ErrInvalidGroupBy is returned when a time series is grouped by a field not
+// listed in TimeSeriesGroupFields
+var ErrInvalidGroupBy = errors.New("invalid group-by field")
+
+// ErrInvalidRange is returned when a time series would start at or after its
+// end, e.g. for a From in the future without a To
+var ErrInvalidRange = errors.New("invalid time range: from must be before to")
+
+// BucketSize defines the width of the buckets of a time series
+type BucketSize string
+
+const (
+	BucketMinute BucketSize = "minute"
+	BucketHour   BucketSize = "hour"
+	BucketDay    BucketSize = "day"
+)
+
+// Seconds returns the width of the bucket in seconds, or zero for an unknown size
+func (b BucketSize) Seconds() int {
+	switch b {
+	case BucketMinute:
+		return 60
+	case BucketHour:
+		return 60 * 60
+	case BucketDay:
+		return 24 * 60 * 60
+	default:
+		return 0
+	}
+}
+
+// defaultSpan returns the time range covered when a query does not set From
+func (b BucketSize) defaultSpan() int {
+	switch b {
+	case BucketMinute:
+		return 60 * 60
+	case BucketDay:
+		return 30 * 24 * 60 * 60
+	default:
+		return 24 * 60 * 60
+	}
+}
+
+// TimeSeriesGroupFields lists the report fields a time series can be grouped by
+var TimeSeriesGroupFields = []string{
+	domain.FieldViolatedDirective,
+	domain.FieldEffectiveDirective,
+	domain.FieldDisposition,
+}
+
+// TimeSeriesParams defines the buckets and grouping of a time series. The time
+// range is taken from the filter's From and To.
+type TimeSeriesParams struct {
+	Bucket BucketSize
+	// GroupBy is one of TimeSeriesGroupFields, or empty for a single series
+	GroupBy string
+	Filter  ReportFilter
+}
+
+// Normalize applies the default bucket size and time range and aligns the range
+// to bucket boundaries, so that To is exclusive and the range holds whole buckets.
+// It returns ErrInvalidRange when the range is empty.
+func (p *TimeSeriesParams) Normalize(now time.Time) error {
+	if p.GroupBy != "" && !slices.Contains(TimeSeriesGroupFields, p.GroupBy) {
+		return fmt.Errorf("%w %q", ErrInvalidGroupBy, p.GroupBy)
+	}
+
+	if p.Bucket == "" {
+		p.Bucket = BucketHour
+	}
+	size := p.Bucket.Seconds()
+	if size == 0 {
+		return ErrInvalidBucket
+	}
+
+	if p.Filter.To == 0 {
+		p.Filter.To = int(now.Unix()) + 1
+	}
+	if p.Filter.From == 0 {
+		p.Filter.From = p.Filter.To - p.Bucket.defaultSpan()
+	}
+
+	p.Filter.From -= floorMod(p.Filter.From, size)
+	if remainder := floorMod(p.Filter.To, size); remainder != 0 {
+		p.Filter.To += size - remainder
+	}
+
+	if p.Filter.From >= p.Filter.To {
+		return ErrInvalidRange
+	}
+	if (p.Filter.To-p.Filter.From)/size > MaxTimeSeriesBuckets {
+		return ErrTooManyBuckets
+	}
+	return nil
+}
+
+// TimeSeriesCount is the number of reports of a group within the bucket starting at Bucket
+type TimeSeriesCount struct {
+	Bucket int    `bson:"bucket"`
+	Group  string `bson:"group"`
+	Count  int    `bson:"count"`
+}
+
+// TimeSeriesPoint is the number of reports within the bucket starting at Time, in Unix seconds
+type TimeSeriesPoint struct {
+	Time  int `json:"time"`
+	Count int `json:"count"`
+}
+
+// TimeSeriesGroup is the series of a single group value
+type TimeSeriesGroup struct {
+	Group  string            `json:"group"`
+	Points []TimeSeriesPoint `json:"points"`
+}
+
+// TimeSeries is a histogram of reports over time with a point for every bucket
+type TimeSeries struct {
+	Bucket  BucketSize        `json:"bucket"`
+	From    int               `json:"from"`
+	To      int               `json:"to"`
+	GroupBy string            `json:"groupby,omitempty"`
+	Series  []TimeSeriesGroup `json:"series"`
+}
+
+func (s *statisticsService) GetTimeSeries(ctx context.Context, params TimeSeriesParams) (*TimeSeries, error) {
+	params.Filter = params.Filter.excludingNoiseByDefault()
+	if err := params.Normalize(time.Now()); err != nil {
+		return nil, err
+	}
+
+	counts, err := s.repo.GetTimeSeriesCounts(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildTimeSeries(params, counts), nil
+}
+
+// buildTimeSeries zero-fills the sparse counts into one series per group, ordered by group
+func buildTimeSeries(params TimeSeriesParams, counts []TimeSeriesCount) *TimeSeries {
+	size := params.Bucket.Seconds()
+	buckets := (params.Filter.To - params.Filter.From) / size
+
+	emptyPoints := func() []TimeSeriesPoint {
+		points := make([]TimeSeriesPoint, buckets)
+		for i := range points {
+			points[i].Time = params.Filter.From + i*size
+		}
+		return points
+	}
+
+	byGroup := map[string][]TimeSeriesPoint{}
+	if params.GroupBy == "" {
+		byGroup[""] = emptyPoints()
+	}
+	for _, count := range counts {
+		points, ok := byGroup[count.Group]
+		if !ok {
+			points = emptyPoints()
+			byGroup[count.Group] = points
+		}
+		if index := (count.Bucket - params.Filter.From) / size; index >= 0 && index < buckets {
+			points[index].Count += count.Count
+		}
+	}
+
+	series := &TimeSeries{
+		Bucket:  params.Bucket,
+		From:    params.Filter.From,
+		To:      params.Filter.To,
+		GroupBy: params.GroupBy,
+		Series:  make([]TimeSeriesGroup, 0, len(byGroup)),
+	}
+	for group, points := range byGroup {
+		series.Series = append(series.Series, TimeSeriesGroup{Group: group, Points: points})
+	}
+	sort.Slice(series.Series, func(i, j int) bool {
+		return series.Series[i].Group < series.Series[j].Group
+	})
+
+	return series
+}
+
+// floorMod returns the non-negative remainder of a divided by b
+func floorMod(a, b int) int {
+	return ((a % b) + b) % b
+}
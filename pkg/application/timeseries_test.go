@@ -0,0 +1,126 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// timeSeriesRepository returns fixed time series counts
+type timeSeriesRepository struct {
+	StatisticsRepository
+	params TimeSeriesParams
+	counts []TimeSeriesCount
+}
+
+func (r *timeSeriesRepository) GetTimeSeriesCounts(ctx context.Context, params TimeSeriesParams) ([]TimeSeriesCount, error) {
+	r.params = params
+	return r.counts, nil
+}
+
+func TestTimeSeriesParamsNormalize(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name         string
+		params       TimeSeriesParams
+		expectedFrom int
+		expectedTo   int
+		expectedErr  error
+	}{
+		{
+			name:         "Defaults",
+			params:       TimeSeriesParams{},
+			expectedFrom: 1699912800,
+			expectedTo:   1700002800,
+		},
+		{
+			name: "Aligned To Buckets",
+			params: TimeSeriesParams{
+				Bucket: BucketMinute,
+				Filter: ReportFilter{From: 1700000010, To: 1700000130},
+			},
+			expectedFrom: 1699999980,
+			expectedTo:   1700000160,
+		},
+		{
+			name:        "Unknown Bucket",
+			params:      TimeSeriesParams{Bucket: "week"},
+			expectedErr: ErrInvalidBucket,
+		},
+		{
+			name:        "Unknown Group By",
+			params:      TimeSeriesParams{GroupBy: "clientip; DROP TABLE reports"},
+			expectedErr: ErrInvalidGroupBy,
+		},
+		{
+			name:        "Future From Without To",
+			params:      TimeSeriesParams{Filter: ReportFilter{From: 1700000000 + 48*60*60}},
+			expectedErr: ErrInvalidRange,
+		},
+		{
+			name:        "From After To",
+			params:      TimeSeriesParams{Filter: ReportFilter{From: 1700007200, To: 1700000000}},
+			expectedErr: ErrInvalidRange,
+		},
+		{
+			name: "Too Many Buckets",
+			params: TimeSeriesParams{
+				Bucket: BucketMinute,
+				Filter: ReportFilter{From: 1600000000, To: 1700000000},
+			},
+			expectedErr: ErrTooManyBuckets,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Normalize(now)
+			assert.ErrorIs(t, err, tt.expectedErr)
+			if tt.expectedErr == nil {
+				assert.Equal(t, tt.expectedFrom, tt.params.Filter.From)
+				assert.Equal(t, tt.expectedTo, tt.params.Filter.To)
+			}
+		})
+	}
+}
+
+func TestGetTimeSeriesZeroFills(t *testing.T) {
+	repo := &timeSeriesRepository{counts: []TimeSeriesCount{
+		{Bucket: 3600, Group: "script-src", Count: 4},
+		{Bucket: 7200, Group: "img-src", Count: 1},
+		{Bucket: 0, Group: "script-src", Count: 2},
+	}}
+	service := NewStatisticsService(repo)
+
+	series, err := service.GetTimeSeries(context.Background(), TimeSeriesParams{
+		Bucket:  BucketHour,
+		GroupBy: domain.FieldEffectiveDirective,
+		Filter:  ReportFilter{From: 1, To: 3 * 3600},
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 0, repo.params.Filter.From)
+	assert.Equal(t, 3*3600, repo.params.Filter.To)
+	assert.Equal(t, []TimeSeriesGroup{
+		{Group: "img-src", Points: []TimeSeriesPoint{{Time: 0}, {Time: 3600}, {Time: 7200, Count: 1}}},
+		{Group: "script-src", Points: []TimeSeriesPoint{{Time: 0, Count: 2}, {Time: 3600, Count: 4}, {Time: 7200}}},
+	}, series.Series)
+}
+
+func TestGetTimeSeriesWithoutReports(t *testing.T) {
+	service := NewStatisticsService(&timeSeriesRepository{})
+
+	series, err := service.GetTimeSeries(context.Background(), TimeSeriesParams{
+		Bucket: BucketDay,
+		Filter: ReportFilter{From: 86400, To: 3 * 86400},
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []TimeSeriesGroup{
+		{Group: "", Points: []TimeSeriesPoint{{Time: 86400}, {Time: 2 * 86400}}},
+	}, series.Series)
+}
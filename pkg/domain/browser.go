@@ -0,0 +1,130 @@
+package domain
+
+import "time"
+
+// CSPReport is the legacy CSP Level 2 report envelope sent by browsers to a
+// report-uri endpoint with the application/csp-report content type
+type CSPReport struct {
+	Body CSPReportBody `json:"csp-report"`
+}
+
+// CSPReportBody contains the hyphenated fields of a legacy CSP report
+type CSPReportBody struct {
+	DocumentUri        string `json:"document-uri"`
+	Referrer           string `json:"referrer"`
+	ViolatedDirective  string `json:"violated-directive"`
+	EffectiveDirective string `json:"effective-directive"`
+	OriginalPolicy     string `json:"original-policy"`
+	Disposition        string `json:"disposition"`
+	BlockedUri         string `json:"blocked-uri"`
+	LineNumber         int    `json:"line-number"`
+	ColumnNumber       int    `json:"column-number"`
+	SourceFile         string `json:"source-file"`
+	StatusCode         int    `json:"status-code"`
+	ScriptSample       string `json:"script-sample"`
+}
+
+// ToReportData maps the legacy report body onto ReportData
+func (b CSPReportBody) ToReportData() ReportData {
+	effectiveDirective := b.EffectiveDirective
+	if effectiveDirective == "" {
+		// Older browsers only send violated-directive, which may include the source list
+		effectiveDirective = directiveName(b.ViolatedDirective)
+	}
+
+	disposition := b.Disposition
+	if disposition == "" {
+		disposition = DispositionEnforce
+	}
+
+	return ReportData{
+		DocumentUri:        b.DocumentUri,
+		Referrer:           b.Referrer,
+		ViolatedDirective:  b.ViolatedDirective,
+		EffectiveDirective: effectiveDirective,
+		OriginalPolicy:     b.OriginalPolicy,
+		Disposition:        disposition,
+		BlockedUri:         b.BlockedUri,
+		LineNumber:         b.LineNumber,
+		SourceFile:         b.SourceFile,
+		StatusCode:         b.StatusCode,
+		ScriptSample:       b.ScriptSample,
+	}
+}
+
+// directiveName returns the directive name of a serialized directive such as "script-src 'self'"
+func directiveName(directive string) string {
+	for i, r := range directive {
+		if r == ' ' || r == '\t' {
+			return directive[:i]
+		}
+	}
+	return directive
+}
+
+// ReportingAPIReport is a single entry of a W3C Reporting API batch delivered
+// with the application/reports+json content type
+type ReportingAPIReport struct {
+	Type      string                 `json:"type"`
+	Age       int64                  `json:"age"`
+	URL       string                 `json:"url"`
+	UserAgent string                 `json:"user_agent"`
+	Body      CSPViolationReportBody `json:"body"`
+}
+
+// CSPViolationReportBody contains the camelCase fields of a csp-violation report body
+type CSPViolationReportBody struct {
+	DocumentURL        string `json:"documentURL"`
+	Referrer           string `json:"referrer"`
+	BlockedURL         string `json:"blockedURL"`
+	EffectiveDirective string `json:"effectiveDirective"`
+	OriginalPolicy     string `json:"originalPolicy"`
+	SourceFile         string `json:"sourceFile"`
+	Sample             string `json:"sample"`
+	Disposition        string `json:"disposition"`
+	StatusCode         int    `json:"statusCode"`
+	LineNumber         int    `json:"lineNumber"`
+	ColumnNumber       int    `json:"columnNumber"`
+}
+
+// MaxReportingAPIAge is the oldest age accepted from a Reporting API entry.
+// Browsers deliver queued reports within minutes, so older ages are clamped
+// rather than trusted to backdate the report.
+const MaxReportingAPIAge = 24 * time.Hour
+
+// CSPViolationType is the Reporting API type of CSP violation reports
+const CSPViolationType = "csp-violation"
+
+// IsCSPViolation reports whether the entry carries a CSP violation
+func (r ReportingAPIReport) IsCSPViolation() bool {
+	return r.Type == CSPViolationType
+}
+
+// ToReportData maps the Reporting API entry onto ReportData. The report time is
+// derived from the entry's age, in milliseconds, relative to receivedAt. The
+// age is clamped to [0, MaxReportingAPIAge], so the report time is never in the
+// future nor arbitrarily far in the past.
+func (r ReportingAPIReport) ToReportData(receivedAt time.Time) ReportData {
+	age := min(max(r.Age, 0), MaxReportingAPIAge.Milliseconds())
+
+	documentURL := r.Body.DocumentURL
+	if documentURL == "" {
+		documentURL = r.URL
+	}
+
+	return ReportData{
+		DocumentUri:        documentURL,
+		Referrer:           r.Body.Referrer,
+		ViolatedDirective:  r.Body.EffectiveDirective,
+		EffectiveDirective: r.Body.EffectiveDirective,
+		OriginalPolicy:     r.Body.OriginalPolicy,
+		Disposition:        r.Body.Disposition,
+		BlockedUri:         r.Body.BlockedURL,
+		LineNumber:         r.Body.LineNumber,
+		SourceFile:         r.Body.SourceFile,
+		StatusCode:         r.Body.StatusCode,
+		ScriptSample:       r.Body.Sample,
+		UserAgent:          r.UserAgent,
+		ReportTime:         int(receivedAt.Add(-time.Duration(age) * time.Millisecond).Unix()),
+	}
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportingAPIReportTime(t *testing.T) {
+	receivedAt := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name string
+		age  int64
+		want int
+	}{
+		{"fresh", 0, 1700000000},
+		{"one minute", 60000, 1699999940},
+		{"negative", -3600000, 1700000000},
+		{"oldest", MaxReportingAPIAge.Milliseconds(), 1700000000 - int(MaxReportingAPIAge.Seconds())},
+		{"too old", MaxReportingAPIAge.Milliseconds() + 1000, 1700000000 - int(MaxReportingAPIAge.Seconds())},
+		{"overflowing", math.MaxInt64, 1700000000 - int(MaxReportingAPIAge.Seconds())},
+		{"overflowing negative", math.MinInt64, 1700000000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := ReportingAPIReport{Type: CSPViolationType, Age: tt.age}.ToReportData(receivedAt)
+			assert.Equal(t, tt.want, data.ReportTime)
+		})
+	}
+}
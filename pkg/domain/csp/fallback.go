@@ -0,0 +1,38 @@
+package csp
+
+// fallbacks lists, per fetch directive, the directives consulted in turn when
+// a policy does not set it, following the CSP Level 3 fallback lists
+var fallbacks = map[string][]string{
+	"script-src-elem":  {"script-src", "default-src"},
+	"script-src-attr":  {"script-src", "default-src"},
+	"style-src-elem":   {"style-src", "default-src"},
+	"style-src-attr":   {"style-src", "default-src"},
+	"worker-src":       {"child-src", "script-src", "default-src"},
+	"frame-src":        {"child-src", "default-src"},
+	"child-src":        {"default-src"},
+	"connect-src":      {"default-src"},
+	"fenced-frame-src": {"frame-src", "child-src", "default-src"},
+	"font-src":         {"default-src"},
+	"img-src":          {"default-src"},
+	"manifest-src":     {"default-src"},
+	"media-src":        {"default-src"},
+	"object-src":       {"default-src"},
+	"prefetch-src":     {"default-src"},
+	"script-src":       {"default-src"},
+	"style-src":        {"default-src"},
+}
+
+// Governing returns the directive that controls name in the policy: name
+// itself if set, otherwise the first directive of its fallback list that is
+// set. It returns nil if none of them is set.
+func (p Policy) Governing(name string) *Directive {
+	if directive := p.Directive(name); directive != nil {
+		return directive
+	}
+	for _, fallback := range fallbacks[name] {
+		if directive := p.Directive(fallback); directive != nil {
+			return directive
+		}
+	}
+	return nil
+}
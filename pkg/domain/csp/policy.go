@@ -0,0 +1,156 @@
+// Package csp parses Content Security Policies, such as the original policy
+// of a violation report, into directives and source expressions
+package csp
+
+import (
+	"strings"
+)
+
+// Policy is a parsed Content Security Policy
+type Policy struct {
+	Directives []Directive `json:"directives"`
+}
+
+// Directive is a policy directive with its values. The values of source list
+// directives are classified source expressions; other directives, such as
+// sandbox or report-uri, keep their values as KindValue sources.
+type Directive struct {
+	Name    string   `json:"name"`
+	Sources []Source `json:"sources"`
+}
+
+// sourceListDirectives lists the directives whose values are source expressions
+var sourceListDirectives = map[string]bool{
+	"base-uri":         true,
+	"child-src":        true,
+	"connect-src":      true,
+	"default-src":      true,
+	"fenced-frame-src": true,
+	"font-src":         true,
+	"form-action":      true,
+	"frame-ancestors":  true,
+	"frame-src":        true,
+	"img-src":          true,
+	"manifest-src":     true,
+	"media-src":        true,
+	"navigate-to":      true,
+	"object-src":       true,
+	"prefetch-src":     true,
+	"script-src":       true,
+	"script-src-attr":  true,
+	"script-src-elem":  true,
+	"style-src":        true,
+	"style-src-attr":   true,
+	"style-src-elem":   true,
+	"worker-src":       true,
+}
+
+// IsSourceList reports whether the values of the directive name are source expressions
+func IsSourceList(name string) bool {
+	return sourceListDirectives[name]
+}
+
+// Parse parses a serialized policy such as "default-src 'self'; img-src https:".
+// Directive names are lowercased, empty and malformed directives are skipped and,
+// as browsers do, only the first occurrence of a repeated directive is kept.
+func Parse(policy string) Policy {
+	parsed := Policy{Directives: []Directive{}}
+	seen := make(map[string]bool)
+
+	for _, token := range strings.Split(policy, ";") {
+		fields := strings.Fields(token)
+		if len(fields) == 0 {
+			continue
+		}
+
+		name := strings.ToLower(fields[0])
+		if !isDirectiveName(name) || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		directive := Directive{Name: name, Sources: make([]Source, 0, len(fields)-1)}
+		for _, value := range fields[1:] {
+			if IsSourceList(name) {
+				directive.Sources = append(directive.Sources, ParseSource(value))
+			} else {
+				directive.Sources = append(directive.Sources, Source{Kind: KindValue, Value: value})
+			}
+		}
+		parsed.Directives = append(parsed.Directives, directive)
+	}
+
+	return parsed
+}
+
+// isDirectiveName reports whether name only contains the characters allowed in directive names
+func isDirectiveName(name string) bool {
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+			return false
+		}
+	}
+	return name != ""
+}
+
+// Directive returns the directive called name, or nil if the policy does not set it
+func (p Policy) Directive(name string) *Directive {
+	for i := range p.Directives {
+		if p.Directives[i].Name == name {
+			return &p.Directives[i]
+		}
+	}
+	return nil
+}
+
+// String serializes the policy in the header format
+func (p Policy) String() string {
+	directives := make([]string, len(p.Directives))
+	for i, directive := range p.Directives {
+		directives[i] = directive.String()
+	}
+	return strings.Join(directives, "; ")
+}
+
+// HasKeyword reports whether the directive lists the keyword, such as KeywordSelf
+func (d Directive) HasKeyword(keyword string) bool {
+	for _, source := range d.Sources {
+		if source.Kind == KindKeyword && source.Value == keyword {
+			return true
+		}
+	}
+	return false
+}
+
+// HasSource reports whether the directive lists a source equivalent to
+// expression, comparing schemes, hosts and keywords case-insensitively
+func (d Directive) HasSource(expression string) bool {
+	wanted := ParseSource(expression)
+	for _, source := range d.Sources {
+		if source.equal(wanted) {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveKeyword removes every occurrence of the keyword from the directive
+func (d *Directive) RemoveKeyword(keyword string) {
+	sources := d.Sources[:0]
+	for _, source := range d.Sources {
+		if source.Kind != KindKeyword || source.Value != keyword {
+			sources = append(sources, source)
+		}
+	}
+	d.Sources = sources
+}
+
+// String serializes the directive as its name followed by its values
+func (d Directive) String() string {
+	values := make([]string, 0, len(d.Sources)+1)
+	values = append(values, d.Name)
+	for _, source := range d.Sources {
+		values = append(values, source.Value)
+	}
+	return strings.Join(values, " ")
+}
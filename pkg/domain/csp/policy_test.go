@@ -0,0 +1,128 @@
+package csp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSource(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       Source
+	}{
+		{"'self'", Source{Kind: KindKeyword, Value: KeywordSelf}},
+		{"'SELF'", Source{Kind: KindKeyword, Value: KeywordSelf}},
+		{"'strict-dynamic'", Source{Kind: KindKeyword, Value: KeywordStrictDynamic}},
+		{"'unsafe-inline'", Source{Kind: KindKeyword, Value: KeywordUnsafeInline}},
+		{"'nonce-r4nd0m+/='", Source{Kind: KindNonce, Value: "'nonce-r4nd0m+/='", Digest: "r4nd0m+/="}},
+		{"'sha256-AbC_-1='", Source{Kind: KindHash, Value: "'sha256-AbC_-1='", Algorithm: "sha256", Digest: "AbC_-1="}},
+		{"'SHA512-abc'", Source{Kind: KindHash, Value: "'SHA512-abc'", Algorithm: "sha512", Digest: "abc"}},
+		{"'md5-abc'", Source{Kind: KindValue, Value: "'md5-abc'"}},
+		{"'nonce-'", Source{Kind: KindValue, Value: "'nonce-'"}},
+		{"https:", Source{Kind: KindScheme, Value: "https:", Scheme: "https"}},
+		{"Data:", Source{Kind: KindScheme, Value: "Data:", Scheme: "data"}},
+		{"*", Source{Kind: KindHost, Value: "*", Host: "*", Wildcard: true}},
+		{"example.com", Source{Kind: KindHost, Value: "example.com", Host: "example.com"}},
+		{"*.Example.com", Source{Kind: KindHost, Value: "*.Example.com", Host: "*.example.com", Wildcard: true}},
+		{"https://cdn.example.com:8443/js/", Source{Kind: KindHost, Value: "https://cdn.example.com:8443/js/", Scheme: "https", Host: "cdn.example.com", Port: "8443", Path: "/js/"}},
+		{"wss://*.example.com:*", Source{Kind: KindHost, Value: "wss://*.example.com:*", Scheme: "wss", Host: "*.example.com", Wildcard: true, Port: "*"}},
+		{"example.*", Source{Kind: KindValue, Value: "example.*"}},
+		{"https://", Source{Kind: KindValue, Value: "https://"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseSource(tt.expression))
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	policy := Parse("Default-Src 'self'; script-src 'nonce-abc' 'strict-dynamic' https:;; " +
+		"img-src * data:; sandbox allow-scripts; script-src 'unsafe-inline'; b@d x; report-uri /csp")
+
+	require.Len(t, policy.Directives, 5)
+	assert.Equal(t, []string{"default-src", "script-src", "img-src", "sandbox", "report-uri"}, directiveNames(policy))
+
+	script := policy.Directive("script-src")
+	require.NotNil(t, script)
+	assert.Equal(t, []SourceKind{KindNonce, KindKeyword, KindScheme}, sourceKinds(*script))
+	assert.True(t, script.HasKeyword(KeywordStrictDynamic))
+	// Only the first occurrence of a repeated directive is kept
+	assert.False(t, script.HasKeyword(KeywordUnsafeInline))
+
+	// Values of directives that don't take sources are not classified
+	sandbox := policy.Directive("sandbox")
+	require.NotNil(t, sandbox)
+	assert.Equal(t, []Source{{Kind: KindValue, Value: "allow-scripts"}}, sandbox.Sources)
+
+	assert.Nil(t, policy.Directive("style-src"))
+	assert.Equal(t, "default-src 'self'; script-src 'nonce-abc' 'strict-dynamic' https:; img-src * data:; sandbox allow-scripts; report-uri /csp", policy.String())
+}
+
+func TestParseEmpty(t *testing.T) {
+	for _, policy := range []string{"", " ; ;"} {
+		parsed := Parse(policy)
+		assert.NotNil(t, parsed.Directives)
+		assert.Empty(t, parsed.Directives)
+	}
+
+	parsed := Parse("upgrade-insecure-requests")
+	require.Len(t, parsed.Directives, 1)
+	assert.Empty(t, parsed.Directives[0].Sources)
+	assert.Equal(t, "upgrade-insecure-requests", parsed.String())
+}
+
+func directiveNames(policy Policy) []string {
+	names := make([]string, len(policy.Directives))
+	for i, directive := range policy.Directives {
+		names[i] = directive.Name
+	}
+	return names
+}
+
+func sourceKinds(directive Directive) []SourceKind {
+	kinds := make([]SourceKind, len(directive.Sources))
+	for i, source := range directive.Sources {
+		kinds[i] = source.Kind
+	}
+	return kinds
+}
+
+func TestGoverning(t *testing.T) {
+	policy := Parse("default-src 'none'; script-src 'self' https://CDN.example.com; script-src-attr 'unsafe-hashes'")
+
+	tests := []struct {
+		directive string
+		want      string
+	}{
+		{"script-src-elem", "script-src"},
+		{"script-src-attr", "script-src-attr"},
+		{"worker-src", "script-src"},
+		{"img-src", "default-src"},
+		{"frame-ancestors", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.directive, func(t *testing.T) {
+			governing := policy.Governing(tt.directive)
+			if tt.want == "" {
+				assert.Nil(t, governing)
+				return
+			}
+			require.NotNil(t, governing)
+			assert.Equal(t, tt.want, governing.Name)
+		})
+	}
+
+	script := policy.Directive("script-src")
+	assert.True(t, script.HasSource("https://cdn.example.com"))
+	assert.True(t, script.HasSource("'SELF'"))
+	assert.False(t, script.HasSource("http://cdn.example.com"))
+
+	fallback := policy.Directive("default-src")
+	fallback.RemoveKeyword(KeywordNone)
+	assert.Empty(t, fallback.Sources)
+}
@@ -0,0 +1,149 @@
+package csp
+
+import (
+	"regexp"
+	"strings"
+)
+
+// SourceKind classifies a source expression
+type SourceKind string
+
+const (
+	// KindKeyword is a quoted keyword such as 'self' or 'strict-dynamic'
+	KindKeyword SourceKind = "keyword"
+	// KindNonce is a 'nonce-...' source
+	KindNonce SourceKind = "nonce"
+	// KindHash is a 'sha256-...', 'sha384-...' or 'sha512-...' source
+	KindHash SourceKind = "hash"
+	// KindScheme is a scheme source such as https: or data:
+	KindScheme SourceKind = "scheme"
+	// KindHost is a host source such as https://*.example.com:443/path
+	KindHost SourceKind = "host"
+	// KindValue is any other value, including values of directives that do not take sources
+	KindValue SourceKind = "value"
+)
+
+// Keywords of source lists
+const (
+	KeywordSelf                   = "'self'"
+	KeywordNone                   = "'none'"
+	KeywordUnsafeInline           = "'unsafe-inline'"
+	KeywordUnsafeEval             = "'unsafe-eval'"
+	KeywordUnsafeHashes           = "'unsafe-hashes'"
+	KeywordStrictDynamic          = "'strict-dynamic'"
+	KeywordReportSample           = "'report-sample'"
+	KeywordWasmUnsafeEval         = "'wasm-unsafe-eval'"
+	KeywordUnsafeAllowRedirects   = "'unsafe-allow-redirects'"
+	KeywordInlineSpeculationRules = "'inline-speculation-rules'"
+)
+
+// keywords lists the recognised keywords
+var keywords = map[string]bool{
+	KeywordSelf:                   true,
+	KeywordNone:                   true,
+	KeywordUnsafeInline:           true,
+	KeywordUnsafeEval:             true,
+	KeywordUnsafeHashes:           true,
+	KeywordStrictDynamic:          true,
+	KeywordReportSample:           true,
+	KeywordWasmUnsafeEval:         true,
+	KeywordUnsafeAllowRedirects:   true,
+	KeywordInlineSpeculationRules: true,
+}
+
+// hashAlgorithms lists the algorithms allowed in hash sources
+var hashAlgorithms = map[string]bool{
+	"sha256": true,
+	"sha384": true,
+	"sha512": true,
+}
+
+var (
+	// schemeSource matches a scheme source such as "https:"
+	schemeSource = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9+.-]*):$`)
+	// hostSource matches a host source: an optional scheme, a host that is "*",
+	// starts with "*." or is a plain host name, an optional port and an optional path
+	hostSource = regexp.MustCompile(`^(?:([a-zA-Z][a-zA-Z0-9+.-]*)://)?(\*|(?:\*\.)?[a-zA-Z0-9-]+(?:\.[a-zA-Z0-9-]+)*)(?::([0-9]+|\*))?(/[^;,]*)?$`)
+	// base64Value matches the base64 or base64url value of a nonce or hash
+	base64Value = regexp.MustCompile(`^[a-zA-Z0-9+/_-]+=*$`)
+)
+
+// Source is a classified source expression. Value is the expression as it
+// appears in the policy, with keywords lowercased; the other fields are set
+// according to Kind.
+type Source struct {
+	Kind  SourceKind `json:"kind"`
+	Value string     `json:"value"`
+	// Scheme is the scheme of scheme and host sources, lowercased
+	Scheme string `json:"scheme,omitempty"`
+	// Host is the host of host sources, lowercased and including a leading "*." wildcard
+	Host string `json:"host,omitempty"`
+	// Wildcard is set on host sources matching any subdomain, or any host for "*"
+	Wildcard bool `json:"wildcard,omitempty"`
+	// Port is the port of host sources, "*" for any port
+	Port string `json:"port,omitempty"`
+	// Path is the path of host sources
+	Path string `json:"path,omitempty"`
+	// Algorithm is the hash algorithm of hash sources
+	Algorithm string `json:"algorithm,omitempty"`
+	// Digest is the base64 nonce or hash value
+	Digest string `json:"digest,omitempty"`
+}
+
+// ParseSource classifies a single source expression
+func ParseSource(expression string) Source {
+	lower := strings.ToLower(expression)
+
+	if len(expression) > 2 && strings.HasPrefix(expression, "'") && strings.HasSuffix(expression, "'") {
+		if keywords[lower] {
+			return Source{Kind: KindKeyword, Value: lower}
+		}
+
+		inner := expression[1 : len(expression)-1]
+		prefix, digest, found := strings.Cut(inner, "-")
+		prefix = strings.ToLower(prefix)
+		if found && base64Value.MatchString(digest) {
+			if prefix == "nonce" {
+				return Source{Kind: KindNonce, Value: expression, Digest: digest}
+			}
+			if hashAlgorithms[prefix] {
+				return Source{Kind: KindHash, Value: expression, Algorithm: prefix, Digest: digest}
+			}
+		}
+		return Source{Kind: KindValue, Value: expression}
+	}
+
+	if match := schemeSource.FindStringSubmatch(expression); match != nil {
+		return Source{Kind: KindScheme, Value: expression, Scheme: strings.ToLower(match[1])}
+	}
+
+	if match := hostSource.FindStringSubmatch(expression); match != nil {
+		host := strings.ToLower(match[2])
+		return Source{
+			Kind:     KindHost,
+			Value:    expression,
+			Scheme:   strings.ToLower(match[1]),
+			Host:     host,
+			Wildcard: strings.HasPrefix(host, "*"),
+			Port:     match[3],
+			Path:     match[4],
+		}
+	}
+
+	return Source{Kind: KindValue, Value: expression}
+}
+
+// equal reports whether s and other are the same source expression
+func (s Source) equal(other Source) bool {
+	if s.Kind != other.Kind {
+		return false
+	}
+	switch s.Kind {
+	case KindScheme:
+		return s.Scheme == other.Scheme
+	case KindHost:
+		return s.Scheme == other.Scheme && s.Host == other.Host && s.Port == other.Port && s.Path == other.Path
+	default:
+		return s.Value == other.Value
+	}
+}
@@ -0,0 +1,48 @@
+package domain
+
+// knownDirectives lists the directive names defined by CSP Level 3 and the
+// directives still reported by browsers from earlier levels
+var knownDirectives = map[string]bool{
+	"base-uri":                  true,
+	"block-all-mixed-content":   true,
+	"child-src":                 true,
+	"connect-src":               true,
+	"default-src":               true,
+	"fenced-frame-src":          true,
+	"font-src":                  true,
+	"form-action":               true,
+	"frame-ancestors":           true,
+	"frame-src":                 true,
+	"img-src":                   true,
+	"manifest-src":              true,
+	"media-src":                 true,
+	"navigate-to":               true,
+	"object-src":                true,
+	"plugin-types":              true,
+	"prefetch-src":              true,
+	"report-to":                 true,
+	"report-uri":                true,
+	"require-trusted-types-for": true,
+	"sandbox":                   true,
+	"script-src":                true,
+	"script-src-attr":           true,
+	"script-src-elem":           true,
+	"style-src":                 true,
+	"style-src-attr":            true,
+	"style-src-elem":            true,
+	"trusted-types":             true,
+	"upgrade-insecure-requests": true,
+	"webrtc":                    true,
+	"worker-src":                true,
+}
+
+// IsKnownDirective reports whether name is a CSP directive name
+func IsKnownDirective(name string) bool {
+	return knownDirectives[name]
+}
+
+// Report dispositions
+const (
+	DispositionEnforce = "enforce"
+	DispositionReport  = "report"
+)
@@ -0,0 +1,245 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Issue groups the reports of one violation: reports with the same normalized
+// document path, effective directive, blocked origin and source file share a
+// fingerprint and are counted together
+type Issue struct {
+	Fingerprint        string `bson:"_id" json:"fingerprint"`
+	DocumentPath       string `bson:"documentpath" json:"documentpath"`
+	EffectiveDirective string `bson:"effectivedirective" json:"effectivedirective"`
+	BlockedOrigin      string `bson:"blockedorigin" json:"blockedorigin"`
+	SourceFile         string `bson:"sourcefile" json:"sourcefile"`
+	// FirstSeen and LastSeen are the earliest and latest report times
+	FirstSeen int `bson:"firstseen" json:"firstseen"`
+	LastSeen  int `bson:"lastseen" json:"lastseen"`
+	Count     int `bson:"count" json:"count"`
+
+	Status IssueStatus `bson:"status" json:"status"`
+	// StatusNote, StatusActor and StatusTime describe the latest triage: why,
+	// by whom and when, in Unix seconds, the status was set
+	StatusNote  string `bson:"statusnote" json:"statusnote"`
+	StatusActor string `bson:"statusactor" json:"statusactor"`
+	StatusTime  int    `bson:"statustime" json:"statustime"`
+	// SnoozeUntil is the count at which a snoozed issue reopens
+	SnoozeUntil int `bson:"snoozeuntil" json:"snoozeuntil"`
+	// RegressedAt is the report time that last reopened the resolved issue
+	RegressedAt int `bson:"regressedat" json:"regressedat"`
+}
+
+// IssueStatus is the triage state of an issue
+type IssueStatus string
+
+const (
+	IssueOpen     IssueStatus = "open"
+	IssueResolved IssueStatus = "resolved"
+	IssueIgnored  IssueStatus = "ignored"
+	// IssueSnoozed issues reopen once they receive a given number of further reports
+	IssueSnoozed IssueStatus = "snoozed"
+	// IssueRegressed issues were resolved and received reports after their resolution
+	IssueRegressed IssueStatus = "regressed"
+)
+
+// IssueStatuses lists every issue status
+var IssueStatuses = []IssueStatus{IssueOpen, IssueResolved, IssueIgnored, IssueSnoozed, IssueRegressed}
+
+// IssueStatusChange is a triage decision on an issue
+type IssueStatusChange struct {
+	// Status is open, resolved, ignored or snoozed. Issues only regress on their own.
+	Status IssueStatus `json:"status"`
+	Note   string      `json:"note"`
+	Actor  string      `json:"actor"`
+	// SnoozeCount is the number of further reports a snoozed issue waits for
+	SnoozeCount int `json:"snoozecount"`
+	// Time is when the decision was made, in Unix seconds, set by the server
+	Time int `json:"-"`
+}
+
+// Field names of IssueStatusChange in field errors
+const (
+	FieldIssueStatus      = "status"
+	FieldIssueNote        = "note"
+	FieldIssueActor       = "actor"
+	FieldIssueSnoozeCount = "snoozecount"
+)
+
+// Length limits of IssueStatusChange, in bytes
+const (
+	MaxIssueNoteLength  = 4096
+	MaxIssueActorLength = 256
+)
+
+// Validate checks the change for a status that can be set, an actor and the
+// snooze count. It returns a *ValidationError listing every invalid field, or nil.
+func (c *IssueStatusChange) Validate() error {
+	errs := &ValidationError{}
+
+	switch c.Status {
+	case IssueOpen, IssueResolved, IssueIgnored, IssueSnoozed:
+	case "":
+		errs.add(FieldIssueStatus, "is required")
+	default:
+		errs.add(FieldIssueStatus, "must be open, resolved, ignored or snoozed")
+	}
+
+	if c.Status == IssueSnoozed && c.SnoozeCount < 1 {
+		errs.add(FieldIssueSnoozeCount, "must be a positive number of reports")
+	}
+	if c.Status != IssueSnoozed && c.SnoozeCount != 0 {
+		errs.add(FieldIssueSnoozeCount, "is only allowed when snoozing")
+	}
+
+	if strings.TrimSpace(c.Actor) == "" {
+		errs.add(FieldIssueActor, "is required")
+	} else if len(c.Actor) > MaxIssueActorLength {
+		errs.add(FieldIssueActor, "must be at most %d bytes", MaxIssueActorLength)
+	}
+	if len(c.Note) > MaxIssueNoteLength {
+		errs.add(FieldIssueNote, "must be at most %d bytes", MaxIssueNoteLength)
+	}
+
+	if len(errs.Errors) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// SetStatus applies a triage decision
+func (i *Issue) SetStatus(change IssueStatusChange) {
+	i.Status = change.Status
+	i.StatusNote = change.Note
+	i.StatusActor = change.Actor
+	i.StatusTime = change.Time
+	i.SnoozeUntil = 0
+	if change.Status == IssueSnoozed {
+		i.SnoozeUntil = i.Count + change.SnoozeCount
+	}
+}
+
+// NewIssue returns the issue a single report belongs to, with a count of one
+func NewIssue(data ReportData) Issue {
+	issue := Issue{
+		DocumentPath:       normalizeDocumentPath(data.DocumentUri),
+		EffectiveDirective: strings.ToLower(data.EffectiveDirective),
+		BlockedOrigin:      normalizeBlockedOrigin(data.BlockedUri),
+		SourceFile:         stripQuery(data.SourceFile),
+		FirstSeen:          data.ReportTime,
+		LastSeen:           data.ReportTime,
+		Count:              1,
+		Status:             IssueOpen,
+	}
+
+	hash := sha256.Sum256([]byte(strings.Join([]string{
+		issue.DocumentPath, issue.EffectiveDirective, issue.BlockedOrigin, issue.SourceFile,
+	}, "\x00")))
+	issue.Fingerprint = hex.EncodeToString(hash[:16])
+	return issue
+}
+
+// Merge adds the occurrences of other, an issue with the same fingerprint, to i
+func (i *Issue) Merge(other Issue) {
+	if i.Count == 0 || other.FirstSeen < i.FirstSeen {
+		i.FirstSeen = other.FirstSeen
+	}
+	if other.LastSeen > i.LastSeen {
+		i.LastSeen = other.LastSeen
+	}
+	i.Count += other.Count
+}
+
+// Record adds the occurrences of other, an issue with the same fingerprint,
+// and advances the lifecycle. A resolved issue regresses when seen in or after
+// the second of its resolution, as report times are whole seconds. A snoozed
+// issue reopens once its count reaches SnoozeUntil. Ignored issues keep
+// counting silently. Record reports whether the issue regressed.
+func (i *Issue) Record(other Issue) bool {
+	i.Merge(other)
+
+	switch {
+	case i.Status == IssueResolved && other.LastSeen >= i.StatusTime:
+		i.Status = IssueRegressed
+		i.RegressedAt = other.LastSeen
+		return true
+	case i.Status == IssueSnoozed && i.Count >= i.SnoozeUntil:
+		i.Status = IssueOpen
+		i.SnoozeUntil = 0
+	}
+	return false
+}
+
+// idSegment matches path segments that identify a resource rather than a page:
+// numbers, UUIDs and long hexadecimal strings
+var idSegment = regexp.MustCompile(`^(\d+|[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|[0-9a-fA-F]{16,})$`)
+
+// normalizeDocumentPath reduces a document URI to its host and path, dropping
+// the scheme, query and fragment and replacing ID segments with "{id}", so
+// that e.g. every order page of a shop is one document
+func normalizeDocumentPath(uri string) string {
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Host == "" {
+		return stripQuery(uri)
+	}
+
+	segments := strings.Split(strings.TrimSuffix(parsed.EscapedPath(), "/"), "/")
+	for i, segment := range segments {
+		if idSegment.MatchString(segment) {
+			segments[i] = "{id}"
+		}
+	}
+	path := strings.Join(segments, "/")
+	if path == "" {
+		path = "/"
+	}
+	return strings.ToLower(parsed.Host) + path
+}
+
+// OriginPattern matches the scheme and host of a URI. It is written so that
+// SQL databases and MongoDB evaluate it the same as the regexp package.
+const OriginPattern = `^[a-zA-Z][a-zA-Z0-9+.-]*://[^/?#]+`
+
+var originPattern = regexp.MustCompile(OriginPattern)
+
+// Origin returns the scheme and host of a URI, or an empty string for URIs
+// without a host
+func Origin(uri string) string {
+	return originPattern.FindString(uri)
+}
+
+// schemePattern matches the scheme of a URI
+var schemePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
+
+// normalizeBlockedOrigin reduces a blocked URI to its origin. URIs without a
+// host, such as data: URLs, are reduced to their scheme, and keywords such as
+// "inline" and "eval" are kept.
+func normalizeBlockedOrigin(uri string) string {
+	if origin := Origin(uri); origin != "" {
+		return strings.ToLower(origin)
+	}
+	if scheme := schemePattern.FindString(uri); scheme != "" {
+		return strings.ToLower(scheme)
+	}
+	return strings.ToLower(uri)
+}
+
+// stripQuery removes the query and fragment of a URI
+func stripQuery(uri string) string {
+	if index := strings.IndexAny(uri, "?#"); index >= 0 {
+		return uri[:index]
+	}
+	return uri
+}
+
+// fingerprintPattern matches the fingerprints NewIssue generates
+var fingerprintPattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// IsFingerprint reports whether value is a well-formed issue fingerprint
+func IsFingerprint(value string) bool {
+	return fingerprintPattern.MatchString(value)
+}
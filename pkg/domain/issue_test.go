@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewIssue(t *testing.T) {
+	issue := NewIssue(ReportData{
+		DocumentUri:        "https://Shop.example.com/orders/12345/items/?page=2#top",
+		EffectiveDirective: "script-src-elem",
+		BlockedUri:         "https://CDN.example.net/js/app.js?v=3",
+		SourceFile:         "https://shop.example.com/js/main.js?v=3",
+		ReportTime:         1700000000,
+	})
+
+	assert.Equal(t, "shop.example.com/orders/{id}/items", issue.DocumentPath)
+	assert.Equal(t, "script-src-elem", issue.EffectiveDirective)
+	assert.Equal(t, "https://cdn.example.net", issue.BlockedOrigin)
+	assert.Equal(t, "https://shop.example.com/js/main.js", issue.SourceFile)
+	assert.Equal(t, 1700000000, issue.FirstSeen)
+	assert.Equal(t, 1700000000, issue.LastSeen)
+	assert.Equal(t, 1, issue.Count)
+	assert.True(t, IsFingerprint(issue.Fingerprint))
+}
+
+func TestNewIssueFingerprint(t *testing.T) {
+	base := ReportData{
+		DocumentUri:        "https://example.com/orders/1",
+		EffectiveDirective: "img-src",
+		BlockedUri:         "data:image/png;base64,AAAA",
+	}
+	fingerprint := NewIssue(base).Fingerprint
+
+	tests := []struct {
+		name string
+		edit func(*ReportData)
+		same bool
+	}{
+		{"other order", func(d *ReportData) { d.DocumentUri = "https://example.com/orders/2?ref=mail" }, true},
+		{"other data URL", func(d *ReportData) { d.BlockedUri = "data:image/gif;base64,BBBB" }, true},
+		{"other report time", func(d *ReportData) { d.ReportTime = 1700000000 }, true},
+		{"other page", func(d *ReportData) { d.DocumentUri = "https://example.com/cart" }, false},
+		{"other directive", func(d *ReportData) { d.EffectiveDirective = "font-src" }, false},
+		{"other origin", func(d *ReportData) { d.BlockedUri = "https://img.example.net/a.png" }, false},
+		{"source file", func(d *ReportData) { d.SourceFile = "https://example.com/app.js" }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := base
+			tt.edit(&data)
+			if tt.same {
+				assert.Equal(t, fingerprint, NewIssue(data).Fingerprint)
+			} else {
+				assert.NotEqual(t, fingerprint, NewIssue(data).Fingerprint)
+			}
+		})
+	}
+}
+
+func TestIssueMerge(t *testing.T) {
+	issue := Issue{FirstSeen: 200, LastSeen: 300, Count: 2}
+	issue.Merge(Issue{FirstSeen: 100, LastSeen: 250, Count: 3})
+	issue.Merge(Issue{FirstSeen: 400, LastSeen: 400, Count: 1})
+
+	assert.Equal(t, Issue{FirstSeen: 100, LastSeen: 400, Count: 6}, issue)
+	assert.False(t, IsFingerprint("not-a-fingerprint"))
+}
+
+func TestIssueStatusChangeValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		change IssueStatusChange
+		want   []FieldError
+	}{
+		{"resolve", IssueStatusChange{Status: IssueResolved, Note: "allowed in the policy", Actor: "alice"}, nil},
+		{"snooze", IssueStatusChange{Status: IssueSnoozed, SnoozeCount: 10, Actor: "alice"}, nil},
+		{"missing status and actor", IssueStatusChange{Actor: " "}, []FieldError{
+			{Field: FieldIssueStatus, Message: "is required"},
+			{Field: FieldIssueActor, Message: "is required"},
+		}},
+		{"regressed", IssueStatusChange{Status: IssueRegressed, Actor: "alice"}, []FieldError{
+			{Field: FieldIssueStatus, Message: "must be open, resolved, ignored or snoozed"},
+		}},
+		{"snooze without count", IssueStatusChange{Status: IssueSnoozed, Actor: "alice"}, []FieldError{
+			{Field: FieldIssueSnoozeCount, Message: "must be a positive number of reports"},
+		}},
+		{"count without snooze", IssueStatusChange{Status: IssueIgnored, SnoozeCount: 5, Actor: "alice"}, []FieldError{
+			{Field: FieldIssueSnoozeCount, Message: "is only allowed when snoozing"},
+		}},
+		{"long note", IssueStatusChange{Status: IssueOpen, Note: strings.Repeat("n", MaxIssueNoteLength+1), Actor: "alice"}, []FieldError{
+			{Field: FieldIssueNote, Message: "must be at most 4096 bytes"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.change.Validate()
+			if tt.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.want, validationErr.Errors)
+		})
+	}
+}
+
+func TestIssueRecord(t *testing.T) {
+	t.Run("resolved", func(t *testing.T) {
+		issue := Issue{LastSeen: 100, Count: 1}
+		issue.SetStatus(IssueStatusChange{Status: IssueResolved, Actor: "alice", Time: 200})
+
+		// Reports from before the resolution don't reopen it
+		assert.False(t, issue.Record(Issue{FirstSeen: 150, LastSeen: 150, Count: 1}))
+		assert.Equal(t, IssueResolved, issue.Status)
+
+		assert.True(t, issue.Record(Issue{FirstSeen: 300, LastSeen: 300, Count: 1}))
+		assert.Equal(t, IssueRegressed, issue.Status)
+		assert.Equal(t, 300, issue.RegressedAt)
+		assert.False(t, issue.Record(Issue{FirstSeen: 400, LastSeen: 400, Count: 1}))
+		assert.Equal(t, 4, issue.Count)
+	})
+
+	t.Run("resolved in the same second", func(t *testing.T) {
+		issue := Issue{LastSeen: 100, Count: 1}
+		issue.SetStatus(IssueStatusChange{Status: IssueResolved, Actor: "alice", Time: 200})
+
+		assert.True(t, issue.Record(Issue{FirstSeen: 200, LastSeen: 200, Count: 1}))
+		assert.Equal(t, IssueRegressed, issue.Status)
+		assert.Equal(t, 200, issue.RegressedAt)
+	})
+
+	t.Run("snoozed", func(t *testing.T) {
+		issue := Issue{Count: 5}
+		issue.SetStatus(IssueStatusChange{Status: IssueSnoozed, SnoozeCount: 3, Actor: "alice", Time: 200})
+		assert.Equal(t, 8, issue.SnoozeUntil)
+
+		issue.Record(Issue{Count: 2})
+		assert.Equal(t, IssueSnoozed, issue.Status)
+		issue.Record(Issue{Count: 1})
+		assert.Equal(t, IssueOpen, issue.Status)
+		assert.Equal(t, 0, issue.SnoozeUntil)
+	})
+
+	t.Run("ignored", func(t *testing.T) {
+		issue := Issue{Count: 1}
+		issue.SetStatus(IssueStatusChange{Status: IssueIgnored, Actor: "alice", Time: 200})
+		assert.False(t, issue.Record(Issue{LastSeen: 300, Count: 100}))
+		assert.Equal(t, IssueIgnored, issue.Status)
+		assert.Equal(t, 101, issue.Count)
+	})
+}
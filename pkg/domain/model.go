@@ -17,9 +17,37 @@ type ReportData struct {
 	ClientIP           string `bson:"clientip" json:"clientip"`
 	UserAgent          string `bson:"useragent" json:"useragent"`
 	ReportTime         int    `bson:"reporttime" json:"reporttime"`
+	// NoiseCategory and NoiseReason are set by the NoiseClassifier on ingest
+	// when the report is noise, such as a violation caused by a browser extension
+	NoiseCategory string `bson:"noisecategory" json:"noisecategory"`
+	NoiseReason   string `bson:"noisereason" json:"noisereason"`
+	// Fingerprint identifies the Issue the report belongs to. It is set on
+	// ingest for reports that are not noise.
+	Fingerprint string `bson:"fingerprint" json:"fingerprint"`
 }
 
 type Report struct {
 	ID     primitive.ObjectID `bson:"_id" json:"_id"`
 	Report ReportData         `bson:"report" json:"report"`
 }
+
+// Names of the ReportData fields as stored, shared by filters, aggregations and storage backends
+const (
+	FieldDocumentUri        = "documenturi"
+	FieldReferrer           = "referrer"
+	FieldViolatedDirective  = "violateddirective"
+	FieldEffectiveDirective = "effectivedirective"
+	FieldOriginalPolicy     = "originalpolicy"
+	FieldDisposition        = "disposition"
+	FieldBlockedUri         = "blockeduri"
+	FieldLineNumber         = "linenumber"
+	FieldSourceFile         = "sourcefile"
+	FieldStatusCode         = "statuscode"
+	FieldScriptSample       = "scriptsample"
+	FieldClientIP           = "clientip"
+	FieldUserAgent          = "useragent"
+	FieldReportTime         = "reporttime"
+	FieldNoiseCategory      = "noisecategory"
+	FieldNoiseReason        = "noisereason"
+	FieldFingerprint        = "fingerprint"
+)
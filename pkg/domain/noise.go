@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// extensionSchemes lists the URL schemes under which browsers load extension resources
+var extensionSchemes = map[string]bool{
+	"chrome-extension":     true,
+	"moz-extension":        true,
+	"ms-browser-extension": true,
+	"safari-extension":     true,
+	"safari-web-extension": true,
+}
+
+// IsExtensionURI reports whether uri points into a browser extension. Such
+// violations are caused by the user's extensions, not by the site.
+func IsExtensionURI(uri string) bool {
+	scheme, _, found := strings.Cut(uri, ":")
+	return found && extensionSchemes[strings.ToLower(scheme)]
+}
+
+// Noise categories of the default rules
+const (
+	NoiseExtension = "extension"
+	NoiseBrowser   = "browser"
+	NoiseInjected  = "injected"
+)
+
+// NoiseRule classifies reports whose Field matches Pattern as noise of Category
+type NoiseRule struct {
+	Category string `json:"category"`
+	Reason   string `json:"reason"`
+	// Field is the report field matched, one of NoiseRuleFields
+	Field string `json:"field"`
+	// Pattern is a regular expression in the syntax of the regexp package
+	Pattern string `json:"pattern"`
+}
+
+// noiseRuleFields maps the report fields noise rules can match to their values
+var noiseRuleFields = map[string]func(*ReportData) string{
+	FieldDocumentUri:  func(d *ReportData) string { return d.DocumentUri },
+	FieldBlockedUri:   func(d *ReportData) string { return d.BlockedUri },
+	FieldSourceFile:   func(d *ReportData) string { return d.SourceFile },
+	FieldScriptSample: func(d *ReportData) string { return d.ScriptSample },
+	FieldUserAgent:    func(d *ReportData) string { return d.UserAgent },
+}
+
+// NoiseRuleFields lists the report fields noise rules can match
+var NoiseRuleFields = []string{FieldDocumentUri, FieldBlockedUri, FieldSourceFile, FieldScriptSample, FieldUserAgent}
+
+// DefaultNoiseRules returns the built-in rules: resources of browser
+// extensions, browser-internal pages and scripts injected by well-known ad
+// injectors and security software
+func DefaultNoiseRules() []NoiseRule {
+	const extensionPattern = `(?i)^(chrome|moz|ms-browser|safari|safari-web)-extension:`
+	const browserPattern = `(?i)^(about|chrome|chrome-search|edge|opera|resource|moz-icon|webkit-masked-url):`
+	const injectorPattern = `(?i)^https?://([^/]*\.)?(superfish\.com|jswrite\.com|datafastguru\.info|adsymptotic\.com|cdncache-a\.akamaihd\.net|gc\.kis\.v2\.scr\.kaspersky-labs\.com|nexusrules\.officeapps\.live\.com)(:\d+)?(/|$)`
+
+	return []NoiseRule{
+		{Category: NoiseExtension, Reason: "blocked URI belongs to a browser extension", Field: FieldBlockedUri, Pattern: extensionPattern},
+		{Category: NoiseExtension, Reason: "source file belongs to a browser extension", Field: FieldSourceFile, Pattern: extensionPattern},
+		{Category: NoiseBrowser, Reason: "blocked URI is a browser-internal page", Field: FieldBlockedUri, Pattern: browserPattern},
+		{Category: NoiseBrowser, Reason: "source file is a browser-internal script", Field: FieldSourceFile, Pattern: browserPattern},
+		{Category: NoiseBrowser, Reason: "document is a browser-internal page", Field: FieldDocumentUri, Pattern: browserPattern},
+		{Category: NoiseInjected, Reason: "blocked URI belongs to a known script injector", Field: FieldBlockedUri, Pattern: injectorPattern},
+		{Category: NoiseInjected, Reason: "source file belongs to a known script injector", Field: FieldSourceFile, Pattern: injectorPattern},
+	}
+}
+
+// compiledNoiseRule is a NoiseRule with its compiled pattern and field accessor
+type compiledNoiseRule struct {
+	NoiseRule
+	pattern *regexp.Regexp
+	value   func(*ReportData) string
+}
+
+// NoiseClassifier tags reports with the category and reason of the first rule they match
+type NoiseClassifier struct {
+	rules []compiledNoiseRule
+}
+
+// NewNoiseClassifier compiles rules, which are applied in order
+func NewNoiseClassifier(rules []NoiseRule) (*NoiseClassifier, error) {
+	classifier := &NoiseClassifier{rules: make([]compiledNoiseRule, len(rules))}
+	for i, rule := range rules {
+		if rule.Category == "" {
+			return nil, fmt.Errorf("noise rule %d: category is required", i)
+		}
+		value, ok := noiseRuleFields[rule.Field]
+		if !ok {
+			return nil, fmt.Errorf("noise rule %d: field %q must be one of %s", i, rule.Field, strings.Join(NoiseRuleFields, ", "))
+		}
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("noise rule %d: %w", i, err)
+		}
+		classifier.rules[i] = compiledNoiseRule{NoiseRule: rule, pattern: pattern, value: value}
+	}
+	return classifier, nil
+}
+
+// DefaultNoiseClassifier returns a classifier applying DefaultNoiseRules
+func DefaultNoiseClassifier() *NoiseClassifier {
+	classifier, err := NewNoiseClassifier(DefaultNoiseRules())
+	if err != nil {
+		panic(err)
+	}
+	return classifier
+}
+
+// Classify sets the noise category and reason of data from the first matching
+// rule, clearing them when no rule matches
+func (c *NoiseClassifier) Classify(data *ReportData) {
+	data.NoiseCategory, data.NoiseReason = "", ""
+	for _, rule := range c.rules {
+		if value := rule.value(data); value != "" && rule.pattern.MatchString(value) {
+			data.NoiseCategory, data.NoiseReason = rule.Category, rule.Reason
+			return
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultNoiseClassifier(t *testing.T) {
+	classifier := DefaultNoiseClassifier()
+
+	tests := []struct {
+		name     string
+		data     ReportData
+		category string
+	}{
+		{"site script", ReportData{BlockedUri: "https://cdn.example.net/app.js", SourceFile: "https://example.com/app.js"}, ""},
+		{"chrome extension", ReportData{BlockedUri: "chrome-extension://abcdef/inject.js"}, NoiseExtension},
+		{"firefox extension source", ReportData{BlockedUri: "inline", SourceFile: "moz-extension://1234/content.js"}, NoiseExtension},
+		{"safari extension", ReportData{BlockedUri: "Safari-Web-Extension://abc/x.js"}, NoiseExtension},
+		{"about page", ReportData{BlockedUri: "about:blank"}, NoiseBrowser},
+		{"masked safari script", ReportData{BlockedUri: "eval", SourceFile: "webkit-masked-url://hidden/"}, NoiseBrowser},
+		{"injector", ReportData{BlockedUri: "https://www.superfish.com/ws/sf_main.js"}, NoiseInjected},
+		{"injector lookalike", ReportData{BlockedUri: "https://superfish.com.example.net/x.js"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := tt.data
+			classifier.Classify(&data)
+			assert.Equal(t, tt.category, data.NoiseCategory)
+			assert.Equal(t, tt.category == "", data.NoiseReason == "")
+		})
+	}
+}
+
+func TestNoiseClassifierCustomRules(t *testing.T) {
+	classifier, err := NewNoiseClassifier([]NoiseRule{
+		{Category: "monitoring", Reason: "synthetic check", Field: FieldUserAgent, Pattern: "Pingdom"},
+	})
+	require.NoError(t, err)
+
+	data := ReportData{UserAgent: "Pingdom.com_bot_version_1.4", NoiseCategory: "stale", NoiseReason: "stale"}
+	classifier.Classify(&data)
+	assert.Equal(t, "monitoring", data.NoiseCategory)
+	assert.Equal(t, "synthetic check", data.NoiseReason)
+
+	// Classification replaces values set by the client
+	data = ReportData{UserAgent: "Mozilla/5.0", NoiseCategory: "forged", NoiseReason: "forged"}
+	classifier.Classify(&data)
+	assert.Empty(t, data.NoiseCategory)
+	assert.Empty(t, data.NoiseReason)
+}
+
+func TestNewNoiseClassifierRejectsInvalidRules(t *testing.T) {
+	tests := []struct {
+		name string
+		rule NoiseRule
+		want string
+	}{
+		{"missing category", NoiseRule{Field: FieldBlockedUri, Pattern: "x"}, "noise rule 0: category is required"},
+		{"unknown field", NoiseRule{Category: "c", Field: "clientip", Pattern: "x"}, `noise rule 0: field "clientip" must be one of`},
+		{"invalid pattern", NoiseRule{Category: "c", Field: FieldBlockedUri, Pattern: "("}, "noise rule 0: error parsing regexp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewNoiseClassifier([]NoiseRule{tt.rule})
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
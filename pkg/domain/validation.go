@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// Field length limits of ReportData, in bytes
+const (
+	MaxURLLength            = 4096
+	MaxDirectiveLength      = 1024
+	MaxScriptSampleLength   = 256
+	MaxOriginalPolicyLength = 16384
+	MaxUserAgentLength      = 512
+)
+
+// FieldError describes why the value of a report field was rejected
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists every invalid field of a report
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, fieldErr := range e.Errors {
+		messages[i] = fieldErr.Field + ": " + fieldErr.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// add records an invalid field
+func (e *ValidationError) add(field, format string, args ...interface{}) {
+	e.Errors = append(e.Errors, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Truncate shortens the free-text fields browsers may fill with arbitrarily
+// long content, the script sample, original policy and user agent, to their
+// limits instead of rejecting the report
+func (d *ReportData) Truncate() {
+	d.ScriptSample = truncate(d.ScriptSample, MaxScriptSampleLength)
+	d.OriginalPolicy = truncate(d.OriginalPolicy, MaxOriginalPolicyLength)
+	d.UserAgent = truncate(d.UserAgent, MaxUserAgentLength)
+}
+
+// truncate shortens s to at most limit bytes without splitting a UTF-8 sequence
+func truncate(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	for limit > 0 && !utf8.RuneStart(s[limit]) {
+		limit--
+	}
+	return s[:limit]
+}
+
+// Validate checks the report for required fields, well-formed URLs, known
+// directive names and field lengths. It returns a *ValidationError listing
+// every invalid field, or nil.
+func (d *ReportData) Validate() error {
+	errs := &ValidationError{}
+
+	if d.DocumentUri == "" {
+		errs.add(FieldDocumentUri, "is required")
+	} else {
+		validateURL(errs, FieldDocumentUri, d.DocumentUri)
+	}
+	if d.Referrer != "" {
+		validateURL(errs, FieldReferrer, d.Referrer)
+	}
+
+	if d.ViolatedDirective == "" && d.EffectiveDirective == "" {
+		errs.add(FieldEffectiveDirective, "is required")
+	}
+	validateDirective(errs, FieldViolatedDirective, d.ViolatedDirective)
+	validateDirective(errs, FieldEffectiveDirective, d.EffectiveDirective)
+
+	if d.Disposition != "" && d.Disposition != DispositionEnforce && d.Disposition != DispositionReport {
+		errs.add(FieldDisposition, "must be %q or %q", DispositionEnforce, DispositionReport)
+	}
+
+	// Blocked URIs and source files may also be keywords such as "inline" or "eval"
+	validateLength(errs, FieldBlockedUri, d.BlockedUri, MaxURLLength)
+	validateLength(errs, FieldSourceFile, d.SourceFile, MaxURLLength)
+	validateLength(errs, FieldScriptSample, d.ScriptSample, MaxScriptSampleLength)
+	validateLength(errs, FieldOriginalPolicy, d.OriginalPolicy, MaxOriginalPolicyLength)
+	validateLength(errs, FieldUserAgent, d.UserAgent, MaxUserAgentLength)
+
+	if d.LineNumber < 0 {
+		errs.add(FieldLineNumber, "must not be negative")
+	}
+	if d.StatusCode < 0 || d.StatusCode > 999 {
+		errs.add(FieldStatusCode, "must be between 0 and 999")
+	}
+
+	if len(errs.Errors) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateURL checks that value is an absolute URL within the length limit
+func validateURL(errs *ValidationError, field, value string) {
+	if len(value) > MaxURLLength {
+		errs.add(field, "must not exceed %d bytes", MaxURLLength)
+		return
+	}
+	if parsed, err := url.Parse(value); err != nil || parsed.Scheme == "" {
+		errs.add(field, "must be an absolute URL")
+	}
+}
+
+// validateDirective checks that a serialized directive, if set, names a known directive
+func validateDirective(errs *ValidationError, field, value string) {
+	if value == "" {
+		return
+	}
+	if len(value) > MaxDirectiveLength {
+		errs.add(field, "must not exceed %d bytes", MaxDirectiveLength)
+		return
+	}
+	if name := directiveName(value); !IsKnownDirective(name) {
+		errs.add(field, "unknown directive %q", name)
+	}
+}
+
+// validateLength checks that value does not exceed limit bytes
+func validateLength(errs *ValidationError, field, value string, limit int) {
+	if len(value) > limit {
+		errs.add(field, "must not exceed %d bytes", limit)
+	}
+}
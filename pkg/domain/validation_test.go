@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	valid := ReportData{
+		DocumentUri:        "https://example.com/page",
+		Referrer:           "https://example.com/",
+		ViolatedDirective:  "script-src 'self'",
+		EffectiveDirective: "script-src",
+		Disposition:        DispositionEnforce,
+		BlockedUri:         "inline",
+		LineNumber:         12,
+		StatusCode:         200,
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*ReportData)
+		want   []FieldError
+	}{
+		{"valid", func(d *ReportData) {}, nil},
+		{"only violated directive", func(d *ReportData) { d.EffectiveDirective = "" }, nil},
+		{"missing document", func(d *ReportData) { d.DocumentUri = "" }, []FieldError{{FieldDocumentUri, "is required"}}},
+		{"relative document", func(d *ReportData) { d.DocumentUri = "/page" }, []FieldError{{FieldDocumentUri, "must be an absolute URL"}}},
+		{"malformed referrer", func(d *ReportData) { d.Referrer = "http://[::1" }, []FieldError{{FieldReferrer, "must be an absolute URL"}}},
+		{"overlong document", func(d *ReportData) { d.DocumentUri = "https://example.com/" + strings.Repeat("a", MaxURLLength) }, []FieldError{{FieldDocumentUri, "must not exceed 4096 bytes"}}},
+		{"missing directives", func(d *ReportData) { d.ViolatedDirective, d.EffectiveDirective = "", "" }, []FieldError{{FieldEffectiveDirective, "is required"}}},
+		{"unknown directive", func(d *ReportData) { d.ViolatedDirective = "scirpt-src 'self'" }, []FieldError{{FieldViolatedDirective, `unknown directive "scirpt-src"`}}},
+		{"unknown disposition", func(d *ReportData) { d.Disposition = "block" }, []FieldError{{FieldDisposition, `must be "enforce" or "report"`}}},
+		{"negative line number", func(d *ReportData) { d.LineNumber = -1 }, []FieldError{{FieldLineNumber, "must not be negative"}}},
+		{"overlong sample", func(d *ReportData) { d.ScriptSample = strings.Repeat("a", MaxScriptSampleLength+1) }, []FieldError{{FieldScriptSample, "must not exceed 256 bytes"}}},
+		{"several fields", func(d *ReportData) { d.DocumentUri, d.StatusCode = "", 1000 }, []FieldError{
+			{FieldDocumentUri, "is required"},
+			{FieldStatusCode, "must be between 0 and 999"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := valid
+			tt.modify(&data)
+
+			err := data.Validate()
+			if tt.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.want, validationErr.Errors)
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	data := ReportData{
+		ScriptSample:   strings.Repeat("a", MaxScriptSampleLength-1) + "€",
+		OriginalPolicy: "default-src 'self'",
+	}
+	data.Truncate()
+
+	// The multi-byte character crossing the limit is dropped whole
+	assert.Equal(t, strings.Repeat("a", MaxScriptSampleLength-1), data.ScriptSample)
+	assert.Equal(t, "default-src 'self'", data.OriginalPolicy)
+	assert.NoError(t, (&ReportData{DocumentUri: "https://example.com/", EffectiveDirective: "img-src", ScriptSample: data.ScriptSample}).Validate())
+}
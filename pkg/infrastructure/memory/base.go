@@ -0,0 +1,37 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryRepository implements the application.Repository interface in memory.
+// It is safe for concurrent use and loses all reports when the process exits,
+// which suits tests and ephemeral deployments.
+type MemoryRepository struct {
+	mu      sync.RWMutex
+	reports []domain.Report
+	byID    map[primitive.ObjectID]int
+	issues  map[string]domain.Issue
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		byID:   make(map[primitive.ObjectID]int),
+		issues: make(map[string]domain.Issue),
+	}
+}
+
+// Close implements the Close method required by the Repository interface
+func (r *MemoryRepository) Close(ctx context.Context) error {
+	return nil
+}
+
+// Ping implements HealthRepository.Ping. The repository is always reachable.
+func (r *MemoryRepository) Ping(ctx context.Context) error {
+	return nil
+}
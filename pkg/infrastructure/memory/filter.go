@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"strings"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+)
+
+// stringField returns the value of a string report field
+func stringField(data *domain.ReportData, field string) string {
+	switch field {
+	case domain.FieldDocumentUri:
+		return data.DocumentUri
+	case domain.FieldReferrer:
+		return data.Referrer
+	case domain.FieldViolatedDirective:
+		return data.ViolatedDirective
+	case domain.FieldEffectiveDirective:
+		return data.EffectiveDirective
+	case domain.FieldOriginalPolicy:
+		return data.OriginalPolicy
+	case domain.FieldDisposition:
+		return data.Disposition
+	case domain.FieldBlockedUri:
+		return data.BlockedUri
+	case domain.FieldSourceFile:
+		return data.SourceFile
+	case domain.FieldScriptSample:
+		return data.ScriptSample
+	case domain.FieldClientIP:
+		return data.ClientIP
+	case domain.FieldUserAgent:
+		return data.UserAgent
+	case domain.FieldNoiseCategory:
+		return data.NoiseCategory
+	case domain.FieldNoiseReason:
+		return data.NoiseReason
+	case domain.FieldFingerprint:
+		return data.Fingerprint
+	default:
+		return ""
+	}
+}
+
+// matchesFilter reports whether a report satisfies every condition of filter
+func matchesFilter(data *domain.ReportData, filter application.ReportFilter) bool {
+	for _, fieldFilter := range filter.FieldFilters() {
+		if !matchesString(stringField(data, fieldFilter.Field), fieldFilter.StringFilter) {
+			return false
+		}
+	}
+
+	if !filter.Noise.Matches(data.NoiseCategory) {
+		return false
+	}
+
+	if filter.From != 0 && data.ReportTime < filter.From {
+		return false
+	}
+	if filter.To != 0 && data.ReportTime >= filter.To {
+		return false
+	}
+
+	return true
+}
+
+// matchesString reports whether value satisfies a case-sensitive string filter
+func matchesString(value string, filter application.StringFilter) bool {
+	switch filter.Match {
+	case application.MatchPrefix:
+		return strings.HasPrefix(value, filter.Value)
+	case application.MatchContains:
+		return strings.Contains(value, filter.Value)
+	default:
+		return value == filter.Value
+	}
+}
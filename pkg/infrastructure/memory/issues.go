@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+)
+
+// RecordIssues implements IssuesRepository.RecordIssues. New issues are stored as open.
+func (r *MemoryRepository) RecordIssues(ctx context.Context, issues []domain.Issue) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var regressed []string
+	for _, issue := range issues {
+		stored, ok := r.issues[issue.Fingerprint]
+		if !ok {
+			issue.Status = domain.IssueOpen
+			r.issues[issue.Fingerprint] = issue
+			continue
+		}
+		if stored.Record(issue) {
+			regressed = append(regressed, issue.Fingerprint)
+		}
+		r.issues[issue.Fingerprint] = stored
+	}
+
+	return regressed, nil
+}
+
+// GetIssue implements IssuesRepository.GetIssue
+func (r *MemoryRepository) GetIssue(ctx context.Context, fingerprint string) (*domain.Issue, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	issue, ok := r.issues[fingerprint]
+	if !ok {
+		return nil, application.ErrNotFound
+	}
+	return &issue, nil
+}
+
+// ListIssues implements IssuesRepository.ListIssues
+func (r *MemoryRepository) ListIssues(ctx context.Context, query application.IssueQuery) (*application.IssuePage, error) {
+	r.mu.RLock()
+	issues := make([]domain.Issue, 0, len(r.issues))
+	for _, issue := range r.issues {
+		if slices.Contains(query.Statuses, issue.Status) {
+			issues = append(issues, issue)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(issues, func(i, j int) bool {
+		return query.Sort.Less(issues[i], issues[j])
+	})
+
+	page := &application.IssuePage{Issues: []domain.Issue{}}
+	if query.IncludeTotal {
+		total := int64(len(issues))
+		page.Total = &total
+	}
+
+	if query.After != nil {
+		issues = slices.DeleteFunc(issues, func(issue domain.Issue) bool {
+			return !query.After.Follows(issue)
+		})
+	}
+	if len(issues) > query.Limit {
+		issues = issues[:query.Limit]
+		page.Next = application.NewIssueCursor(query.Sort, issues[query.Limit-1])
+	}
+	if len(issues) > 0 {
+		page.Issues = issues
+	}
+
+	return page, nil
+}
+
+// SetIssueStatus implements IssuesRepository.SetIssueStatus
+func (r *MemoryRepository) SetIssueStatus(ctx context.Context, fingerprint string, change domain.IssueStatusChange) (*domain.Issue, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	issue, ok := r.issues[fingerprint]
+	if !ok {
+		return nil, application.ErrNotFound
+	}
+	issue.SetStatus(change)
+	r.issues[fingerprint] = issue
+	return &issue, nil
+}
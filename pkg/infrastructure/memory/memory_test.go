@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application/repositorytest"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRepositoryContract(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) application.Repository {
+		return NewMemoryRepository()
+	})
+}
+
+func TestCreateReportsRejectsDuplicateIDs(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+
+	existing := domain.Report{ID: primitive.NewObjectID()}
+	require.NoError(t, repo.CreateReport(ctx, &existing))
+
+	err := repo.CreateReports(ctx, []domain.Report{{ID: primitive.NewObjectID()}, existing})
+	assert.Error(t, err)
+
+	page, err := repo.ListReports(ctx, application.ReportQuery{Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, page.Reports, 1, "a rejected batch must not be stored partially")
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	repo := NewMemoryRepository()
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, repo.CreateReport(ctx, &domain.Report{ID: primitive.NewObjectID()}))
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := repo.GetTopIPs(ctx, application.StatisticsParams{Limit: 10})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	page, err := repo.ListReports(ctx, application.ReportQuery{Limit: 100, IncludeTotal: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(20), *page.Total)
+}
@@ -0,0 +1,133 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// errDuplicateID is returned when a report is created under an ID already in use
+var errDuplicateID = errors.New("a report with this ID already exists")
+
+// CreateReport implements ReportsRepository.CreateReport
+func (r *MemoryRepository) CreateReport(ctx context.Context, report *domain.Report) error {
+	return r.CreateReports(ctx, []domain.Report{*report})
+}
+
+// CreateReports implements ReportsRepository.CreateReports. Either all reports
+// are stored or, if an ID is already taken, none are.
+func (r *MemoryRepository) CreateReports(ctx context.Context, reports []domain.Report) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	seen := make(map[primitive.ObjectID]bool, len(reports))
+	for _, report := range reports {
+		if _, exists := r.byID[report.ID]; exists || seen[report.ID] {
+			return errDuplicateID
+		}
+		seen[report.ID] = true
+	}
+
+	for _, report := range reports {
+		r.byID[report.ID] = len(r.reports)
+		r.reports = append(r.reports, report)
+	}
+
+	return nil
+}
+
+// GetReport implements ReportsRepository.GetReport
+func (r *MemoryRepository) GetReport(ctx context.Context, id string) (*domain.Report, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, application.ErrInvalidID
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	index, ok := r.byID[objectID]
+	if !ok {
+		return nil, application.ErrNotFound
+	}
+
+	report := r.reports[index]
+	return &report, nil
+}
+
+// ListReports implements ReportsRepository.ListReports using keyset pagination on report time and ID
+func (r *MemoryRepository) ListReports(ctx context.Context, query application.ReportQuery) (*application.ReportPage, error) {
+	var cursor *domain.Report
+	if query.After != nil {
+		afterID, err := primitive.ObjectIDFromHex(query.After.ID)
+		if err != nil {
+			return nil, application.ErrInvalidCursor
+		}
+		cursor = &domain.Report{ID: afterID, Report: domain.ReportData{ReportTime: query.After.ReportTime}}
+	}
+
+	matches := r.matching(query.Filter)
+
+	page := &application.ReportPage{}
+	if query.IncludeTotal {
+		total := int64(len(matches))
+		page.Total = &total
+	}
+
+	oldestFirst := query.Sort == application.SortOldest
+	sort.Slice(matches, func(i, j int) bool {
+		if oldestFirst {
+			return reportBefore(matches[i], matches[j])
+		}
+		return reportBefore(matches[j], matches[i])
+	})
+
+	reports := []domain.Report{}
+	for _, report := range matches {
+		// Skip the reports up to and including the cursor
+		if cursor != nil && (oldestFirst && !reportBefore(*cursor, report) || !oldestFirst && !reportBefore(report, *cursor)) {
+			continue
+		}
+
+		// Collect one extra report to find out whether another page follows
+		reports = append(reports, report)
+		if len(reports) > query.Limit {
+			break
+		}
+	}
+
+	if len(reports) > query.Limit {
+		reports = reports[:query.Limit]
+		page.Next = application.NewReportCursor(reports[len(reports)-1])
+	}
+	page.Reports = reports
+
+	return page, nil
+}
+
+// matching returns copies of the reports satisfying filter in insertion order
+func (r *MemoryRepository) matching(filter application.ReportFilter) []domain.Report {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matches []domain.Report
+	for i := range r.reports {
+		if matchesFilter(&r.reports[i].Report, filter) {
+			matches = append(matches, r.reports[i])
+		}
+	}
+	return matches
+}
+
+// reportBefore orders reports by report time and then by ID, the order of the
+// other backends' indexes
+func reportBefore(a, b domain.Report) bool {
+	if a.Report.ReportTime != b.Report.ReportTime {
+		return a.Report.ReportTime < b.Report.ReportTime
+	}
+	return a.ID.Hex() < b.ID.Hex()
+}
@@ -0,0 +1,163 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+)
+
+// topEntry is a value with its occurrence count
+type topEntry struct {
+	value string
+	count int
+}
+
+// GetTopIPs implements StatisticsRepository.GetTopIPs
+func (r *MemoryRepository) GetTopIPs(ctx context.Context, params application.StatisticsParams) ([]application.TopIPResult, error) {
+	var results []application.TopIPResult
+	for _, entry := range r.aggregateTop(fieldKey(domain.FieldClientIP), params) {
+		results = append(results, application.TopIPResult{IP: entry.value, Count: entry.count})
+	}
+
+	return results, nil
+}
+
+// GetTopViolatedDirectives implements StatisticsRepository.GetTopViolatedDirectives
+func (r *MemoryRepository) GetTopViolatedDirectives(ctx context.Context, params application.StatisticsParams) ([]application.TopDirectiveResult, error) {
+	var results []application.TopDirectiveResult
+	for _, entry := range r.aggregateTop(fieldKey(domain.FieldViolatedDirective), params) {
+		results = append(results, application.TopDirectiveResult{Directive: entry.value, Count: entry.count})
+	}
+
+	return results, nil
+}
+
+// GetTopEffectiveDirectives implements StatisticsRepository.GetTopEffectiveDirectives
+func (r *MemoryRepository) GetTopEffectiveDirectives(ctx context.Context, params application.StatisticsParams) ([]application.TopDirectiveResult, error) {
+	var results []application.TopDirectiveResult
+	for _, entry := range r.aggregateTop(fieldKey(domain.FieldEffectiveDirective), params) {
+		results = append(results, application.TopDirectiveResult{Directive: entry.value, Count: entry.count})
+	}
+
+	return results, nil
+}
+
+// GetTopBlockedURIs implements StatisticsRepository.GetTopBlockedURIs
+func (r *MemoryRepository) GetTopBlockedURIs(ctx context.Context, params application.StatisticsParams) ([]application.TopBlockedURIResult, error) {
+	key := fieldKey(domain.FieldBlockedUri)
+	if params.NormalizeOrigin {
+		key = originKey(domain.FieldBlockedUri)
+	}
+
+	var results []application.TopBlockedURIResult
+	for _, entry := range r.aggregateTop(key, params) {
+		results = append(results, application.TopBlockedURIResult{BlockedURI: entry.value, Count: entry.count})
+	}
+
+	return results, nil
+}
+
+// GetTopDocuments implements StatisticsRepository.GetTopDocuments
+func (r *MemoryRepository) GetTopDocuments(ctx context.Context, params application.StatisticsParams) ([]application.TopDocumentResult, error) {
+	var results []application.TopDocumentResult
+	for _, entry := range r.aggregateTop(fieldKey(domain.FieldDocumentUri), params) {
+		results = append(results, application.TopDocumentResult{DocumentURI: entry.value, Count: entry.count})
+	}
+
+	return results, nil
+}
+
+// GetTopSourceFiles implements StatisticsRepository.GetTopSourceFiles
+func (r *MemoryRepository) GetTopSourceFiles(ctx context.Context, params application.StatisticsParams) ([]application.TopSourceFileResult, error) {
+	key := fieldKey(domain.FieldSourceFile)
+	if params.NormalizeOrigin {
+		key = originKey(domain.FieldSourceFile)
+	}
+
+	var results []application.TopSourceFileResult
+	for _, entry := range r.aggregateTop(key, params) {
+		results = append(results, application.TopSourceFileResult{SourceFile: entry.value, Count: entry.count})
+	}
+
+	return results, nil
+}
+
+// fieldKey returns a grouping key reading a string report field
+func fieldKey(field string) func(*domain.ReportData) string {
+	return func(data *domain.ReportData) string {
+		return stringField(data, field)
+	}
+}
+
+// originKey returns a grouping key reducing the URI in a string report field to
+// its origin. Values without an origin are kept as is.
+func originKey(field string) func(*domain.ReportData) string {
+	return func(data *domain.ReportData) string {
+		value := stringField(data, field)
+		if origin := domain.Origin(value); origin != "" {
+			return origin
+		}
+		return value
+	}
+}
+
+// aggregateTop counts the reports matching the params' filter per key and
+// returns the most frequent keys. Ties are broken by value.
+func (r *MemoryRepository) aggregateTop(key func(*domain.ReportData) string, params application.StatisticsParams) []topEntry {
+	counts := make(map[string]int)
+	for _, report := range r.matching(params.Filter) {
+		counts[key(&report.Report)]++
+	}
+
+	entries := make([]topEntry, 0, len(counts))
+	for value, count := range counts {
+		entries = append(entries, topEntry{value: value, count: count})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].count != entries[j].count {
+			return entries[i].count > entries[j].count
+		}
+		return entries[i].value < entries[j].value
+	})
+
+	if params.Limit > 0 && len(entries) > params.Limit {
+		entries = entries[:params.Limit]
+	}
+	return entries
+}
+
+// GetTimeSeriesCounts implements StatisticsRepository.GetTimeSeriesCounts by
+// grouping on the report time rounded down to the bucket size
+func (r *MemoryRepository) GetTimeSeriesCounts(ctx context.Context, params application.TimeSeriesParams) ([]application.TimeSeriesCount, error) {
+	size := params.Bucket.Seconds()
+	if size == 0 {
+		return nil, application.ErrInvalidBucket
+	}
+	if params.GroupBy != "" && !slices.Contains(application.TimeSeriesGroupFields, params.GroupBy) {
+		return nil, fmt.Errorf("%w %q", application.ErrInvalidGroupBy, params.GroupBy)
+	}
+
+	type key struct {
+		bucket int
+		group  string
+	}
+	counts := make(map[key]int)
+	for _, report := range r.matching(params.Filter) {
+		k := key{bucket: report.Report.ReportTime - report.Report.ReportTime%size}
+		if params.GroupBy != "" {
+			k.group = stringField(&report.Report, params.GroupBy)
+		}
+		counts[k]++
+	}
+
+	results := make([]application.TimeSeriesCount, 0, len(counts))
+	for k, count := range counts {
+		results = append(results, application.TimeSeriesCount{Bucket: k.bucket, Group: k.group, Count: count})
+	}
+
+	return results, nil
+}
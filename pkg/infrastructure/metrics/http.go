@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rejectionReasons maps the status codes of refused report submissions to a rejection reason
+var rejectionReasons = map[int]string{
+	http.StatusBadRequest:            "malformed",
+	http.StatusRequestEntityTooLarge: "too_large",
+	http.StatusUnsupportedMediaType:  "unsupported_media_type",
+	http.StatusUnprocessableEntity:   "invalid",
+	http.StatusTooManyRequests:       "rate_limited",
+	http.StatusServiceUnavailable:    "unavailable",
+}
+
+// Middleware records the latency of every request by route and counts report
+// submissions that were refused, by the reason derived from the response
+// status. Report submissions are the POST requests to the ingest routes.
+func (m *Metrics) Middleware(ingestRoutes []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := c.Writer.Status()
+		m.httpRequestDuration.
+			WithLabelValues(c.Request.Method, route, strconv.Itoa(status)).
+			Observe(time.Since(start).Seconds())
+
+		if c.Request.Method == http.MethodPost && slices.Contains(ingestRoutes, c.FullPath()) && status >= http.StatusBadRequest {
+			reason, ok := rejectionReasons[status]
+			if !ok {
+				reason = "error"
+			}
+			m.reportsRejected.WithLabelValues(reason).Inc()
+		}
+	}
+}
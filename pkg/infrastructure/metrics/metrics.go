@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "csp_scout"
+
+// Metrics holds the Prometheus collectors of the API
+type Metrics struct {
+	registry *prometheus.Registry
+
+	reportsIngested      *prometheus.CounterVec
+	reportsRejected      *prometheus.CounterVec
+	httpRequestDuration  *prometheus.HistogramVec
+	repositoryDuration   *prometheus.HistogramVec
+	repositoryErrorCount *prometheus.CounterVec
+}
+
+// New creates the collectors and registers them, along with the Go runtime
+// and process collectors, in a dedicated registry
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		reportsIngested: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reports_ingested_total",
+			Help:      "Number of stored CSP reports by effective directive and disposition.",
+		}, []string{"directive", "disposition"}),
+		reportsRejected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reports_rejected_total",
+			Help:      "Number of rejected report submissions by reason.",
+		}, []string{"reason"}),
+		httpRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Latency of HTTP requests by method, route and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		repositoryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "repository_operation_duration_seconds",
+			Help:      "Latency of repository operations, including statistics aggregations.",
+			Buckets:   []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+		}, []string{"operation"}),
+		repositoryErrorCount: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "repository_errors_total",
+			Help:      "Number of failed repository operations.",
+		}, []string{"operation"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.reportsIngested,
+		m.reportsRejected,
+		m.httpRequestDuration,
+		m.repositoryDuration,
+		m.repositoryErrorCount,
+	)
+
+	return m
+}
+
+// Handler serves the collected metrics in the Prometheus text format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// reportIngested counts a stored report. Unknown directives and dispositions
+// are counted as "other" so that clients cannot inflate the label cardinality.
+func (m *Metrics) reportIngested(data domain.ReportData) {
+	directive := data.EffectiveDirective
+	if !domain.IsKnownDirective(directive) {
+		directive = "other"
+	}
+
+	disposition := data.Disposition
+	if disposition != domain.DispositionEnforce && disposition != domain.DispositionReport {
+		disposition = "other"
+	}
+
+	m.reportsIngested.WithLabelValues(directive, disposition).Inc()
+}
@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubRepository succeeds on writes and fails on statistics
+type stubRepository struct {
+	application.Repository
+}
+
+func (r *stubRepository) CreateReports(ctx context.Context, reports []domain.Report) error {
+	return nil
+}
+
+func (r *stubRepository) GetTopIPs(ctx context.Context, params application.StatisticsParams) ([]application.TopIPResult, error) {
+	return nil, errors.New("aggregation failed")
+}
+
+func scrape(t *testing.T, m *Metrics) string {
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	return w.Body.String()
+}
+
+func TestInstrumentRepository(t *testing.T) {
+	m := New()
+	repo := InstrumentRepository(&stubRepository{}, m)
+
+	err := repo.CreateReports(context.Background(), []domain.Report{
+		{Report: domain.ReportData{EffectiveDirective: "script-src", Disposition: "enforce"}},
+		{Report: domain.ReportData{EffectiveDirective: "script-src", Disposition: "enforce"}},
+		{Report: domain.ReportData{EffectiveDirective: "x-made-up", Disposition: "report"}},
+	})
+	assert.NoError(t, err)
+
+	_, err = repo.GetTopIPs(context.Background(), application.StatisticsParams{})
+	assert.Error(t, err)
+
+	body := scrape(t, m)
+	assert.Contains(t, body, `csp_scout_reports_ingested_total{directive="script-src",disposition="enforce"} 2`)
+	assert.Contains(t, body, `csp_scout_reports_ingested_total{directive="other",disposition="report"} 1`)
+	assert.Contains(t, body, `csp_scout_repository_operation_duration_seconds_count{operation="create_reports"} 1`)
+	assert.Contains(t, body, `csp_scout_repository_operation_duration_seconds_count{operation="top_ips"} 1`)
+	assert.Contains(t, body, `csp_scout_repository_errors_total{operation="top_ips"} 1`)
+	assert.NotContains(t, body, `csp_scout_repository_errors_total{operation="create_reports"}`)
+}
+
+func TestMiddleware(t *testing.T) {
+	m := New()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(m.Middleware([]string{"/api/v1/reports", "/api/v1/reports/csp-report"}))
+	router.POST("/api/v1/reports", func(c *gin.Context) {
+		c.Status(http.StatusBadRequest)
+	})
+	router.POST("/api/v1/reports/csp-report", func(c *gin.Context) {
+		c.Status(http.StatusInternalServerError)
+	})
+	router.POST("/api/v1/issues/:fingerprint/status", func(c *gin.Context) {
+		c.Status(http.StatusNotFound)
+	})
+	router.GET("/api/v1/reports/:id", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodPost, "/api/v1/reports", nil),
+		httptest.NewRequest(http.MethodPost, "/api/v1/reports/csp-report", nil),
+		httptest.NewRequest(http.MethodPost, "/api/v1/issues/abc/status", nil),
+		httptest.NewRequest(http.MethodGet, "/api/v1/reports/abc", nil),
+		httptest.NewRequest(http.MethodGet, "/api/v1/reports/def", nil),
+		httptest.NewRequest(http.MethodPost, "/unknown", nil),
+	} {
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	body := scrape(t, m)
+	assert.Contains(t, body, `csp_scout_http_request_duration_seconds_count{method="GET",route="/api/v1/reports/:id",status="200"} 2`)
+	assert.Contains(t, body, `csp_scout_http_request_duration_seconds_count{method="POST",route="/api/v1/reports",status="400"} 1`)
+	assert.Contains(t, body, `csp_scout_http_request_duration_seconds_count{method="POST",route="unmatched",status="404"} 1`)
+	assert.Contains(t, body, `csp_scout_reports_rejected_total{reason="malformed"} 1`)
+	// Only refused submissions to the ingest routes count, not other writes or unmatched routes
+	assert.Contains(t, body, `csp_scout_reports_rejected_total{reason="error"} 1`)
+}
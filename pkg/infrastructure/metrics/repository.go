@@ -0,0 +1,154 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+)
+
+// instrumentedRepository records the latency and errors of every repository
+// operation and counts stored reports
+type instrumentedRepository struct {
+	application.Repository
+	metrics *Metrics
+}
+
+// InstrumentRepository wraps repo so that its operations are recorded in m
+func InstrumentRepository(repo application.Repository, m *Metrics) application.Repository {
+	return &instrumentedRepository{
+		Repository: repo,
+		metrics:    m,
+	}
+}
+
+// observe records the duration and outcome of an operation started at start
+func (r *instrumentedRepository) observe(operation string, start time.Time, err error) {
+	r.metrics.repositoryDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	// Lookups of unknown or malformed IDs are answers, not failures of the repository
+	if err != nil && !errors.Is(err, application.ErrNotFound) && !errors.Is(err, application.ErrInvalidID) {
+		r.metrics.repositoryErrorCount.WithLabelValues(operation).Inc()
+	}
+}
+
+func (r *instrumentedRepository) CreateReport(ctx context.Context, report *domain.Report) error {
+	start := time.Now()
+	err := r.Repository.CreateReport(ctx, report)
+	r.observe("create_report", start, err)
+	if err == nil {
+		r.metrics.reportIngested(report.Report)
+	}
+	return err
+}
+
+func (r *instrumentedRepository) CreateReports(ctx context.Context, reports []domain.Report) error {
+	start := time.Now()
+	err := r.Repository.CreateReports(ctx, reports)
+	r.observe("create_reports", start, err)
+	if err == nil {
+		for _, report := range reports {
+			r.metrics.reportIngested(report.Report)
+		}
+	}
+	return err
+}
+
+func (r *instrumentedRepository) GetReport(ctx context.Context, id string) (*domain.Report, error) {
+	start := time.Now()
+	report, err := r.Repository.GetReport(ctx, id)
+	r.observe("get_report", start, err)
+	return report, err
+}
+
+func (r *instrumentedRepository) ListReports(ctx context.Context, query application.ReportQuery) (*application.ReportPage, error) {
+	start := time.Now()
+	page, err := r.Repository.ListReports(ctx, query)
+	r.observe("list_reports", start, err)
+	return page, err
+}
+
+func (r *instrumentedRepository) GetTopIPs(ctx context.Context, params application.StatisticsParams) ([]application.TopIPResult, error) {
+	start := time.Now()
+	results, err := r.Repository.GetTopIPs(ctx, params)
+	r.observe("top_ips", start, err)
+	return results, err
+}
+
+func (r *instrumentedRepository) GetTopViolatedDirectives(ctx context.Context, params application.StatisticsParams) ([]application.TopDirectiveResult, error) {
+	start := time.Now()
+	results, err := r.Repository.GetTopViolatedDirectives(ctx, params)
+	r.observe("top_violated_directives", start, err)
+	return results, err
+}
+
+func (r *instrumentedRepository) GetTopEffectiveDirectives(ctx context.Context, params application.StatisticsParams) ([]application.TopDirectiveResult, error) {
+	start := time.Now()
+	results, err := r.Repository.GetTopEffectiveDirectives(ctx, params)
+	r.observe("top_effective_directives", start, err)
+	return results, err
+}
+
+func (r *instrumentedRepository) GetTopBlockedURIs(ctx context.Context, params application.StatisticsParams) ([]application.TopBlockedURIResult, error) {
+	start := time.Now()
+	results, err := r.Repository.GetTopBlockedURIs(ctx, params)
+	r.observe("top_blocked_uris", start, err)
+	return results, err
+}
+
+func (r *instrumentedRepository) GetTopDocuments(ctx context.Context, params application.StatisticsParams) ([]application.TopDocumentResult, error) {
+	start := time.Now()
+	results, err := r.Repository.GetTopDocuments(ctx, params)
+	r.observe("top_documents", start, err)
+	return results, err
+}
+
+func (r *instrumentedRepository) GetTopSourceFiles(ctx context.Context, params application.StatisticsParams) ([]application.TopSourceFileResult, error) {
+	start := time.Now()
+	results, err := r.Repository.GetTopSourceFiles(ctx, params)
+	r.observe("top_source_files", start, err)
+	return results, err
+}
+
+func (r *instrumentedRepository) GetTimeSeriesCounts(ctx context.Context, params application.TimeSeriesParams) ([]application.TimeSeriesCount, error) {
+	start := time.Now()
+	results, err := r.Repository.GetTimeSeriesCounts(ctx, params)
+	r.observe("time_series", start, err)
+	return results, err
+}
+
+func (r *instrumentedRepository) RecordIssues(ctx context.Context, issues []domain.Issue) ([]string, error) {
+	start := time.Now()
+	regressed, err := r.Repository.RecordIssues(ctx, issues)
+	r.observe("record_issues", start, err)
+	return regressed, err
+}
+
+func (r *instrumentedRepository) GetIssue(ctx context.Context, fingerprint string) (*domain.Issue, error) {
+	start := time.Now()
+	issue, err := r.Repository.GetIssue(ctx, fingerprint)
+	r.observe("get_issue", start, err)
+	return issue, err
+}
+
+func (r *instrumentedRepository) ListIssues(ctx context.Context, query application.IssueQuery) (*application.IssuePage, error) {
+	start := time.Now()
+	page, err := r.Repository.ListIssues(ctx, query)
+	r.observe("list_issues", start, err)
+	return page, err
+}
+
+func (r *instrumentedRepository) SetIssueStatus(ctx context.Context, fingerprint string, change domain.IssueStatusChange) (*domain.Issue, error) {
+	start := time.Now()
+	issue, err := r.Repository.SetIssueStatus(ctx, fingerprint, change)
+	r.observe("set_issue_status", start, err)
+	return issue, err
+}
+
+func (r *instrumentedRepository) Ping(ctx context.Context) error {
+	start := time.Now()
+	err := r.Repository.Ping(ctx)
+	r.observe("ping", start, err)
+	return err
+}
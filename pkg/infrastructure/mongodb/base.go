@@ -3,8 +3,10 @@ package mongodb
 import (
 	"context"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 // MongoRepository implements the application.Repository interface
@@ -33,7 +35,37 @@ func (r *MongoRepository) Close(ctx context.Context) error {
 	return r.client.Disconnect(ctx)
 }
 
+// Ping implements HealthRepository.Ping by pinging the primary
+func (r *MongoRepository) Ping(ctx context.Context) error {
+	return translateError(r.client.Ping(ctx, readpref.Primary()))
+}
+
+// EnsureIndexes creates the indexes the repository's queries rely on
+func (r *MongoRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.getCollection().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "report.reporttime", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "report.effectivedirective", Value: 1}, {Key: "report.reporttime", Value: -1}}},
+		{Keys: bson.D{{Key: "report.documenturi", Value: 1}, {Key: "report.reporttime", Value: -1}}},
+		{Keys: bson.D{{Key: "report.blockeduri", Value: 1}}},
+		{Keys: bson.D{{Key: "report.fingerprint", Value: 1}, {Key: "report.reporttime", Value: -1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.getIssuesCollection().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "lastseen", Value: -1}, {Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+	})
+	return err
+}
+
 // getCollection returns the MongoDB collection
 func (r *MongoRepository) getCollection() *mongo.Collection {
 	return r.client.Database(r.database).Collection(r.collection)
 }
+
+// getIssuesCollection returns the collection of issues, named after the reports collection
+func (r *MongoRepository) getIssuesCollection() *mongo.Collection {
+	return r.client.Database(r.database).Collection(r.collection + "_issues")
+}
@@ -0,0 +1,35 @@
+package mongodb
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// documentValidationFailure is the server error code for documents rejected by a collection validator
+const documentValidationFailure = 121
+
+// translateError maps driver errors to the application's sentinel errors,
+// keeping the driver error wrapped for logging. Other errors are returned unchanged.
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return fmt.Errorf("%w: %w", application.ErrNotFound, err)
+	}
+
+	if mongo.IsTimeout(err) || mongo.IsNetworkError(err) || errors.Is(err, mongo.ErrClientDisconnected) {
+		return fmt.Errorf("%w: %w", application.ErrUnavailable, err)
+	}
+
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) && serverErr.HasErrorCode(documentValidationFailure) {
+		return fmt.Errorf("%w: %w", application.ErrValidation, err)
+	}
+
+	return err
+}
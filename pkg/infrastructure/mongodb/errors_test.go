@@ -0,0 +1,42 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestTranslateError(t *testing.T) {
+	unexpected := errors.New("unexpected")
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"nil", nil, nil},
+		{"no documents", mongo.ErrNoDocuments, application.ErrNotFound},
+		{"timeout", context.DeadlineExceeded, application.ErrUnavailable},
+		{"disconnected", mongo.ErrClientDisconnected, application.ErrUnavailable},
+		{"document validation", mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: documentValidationFailure}}}, application.ErrValidation},
+		{"other", unexpected, unexpected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := translateError(tt.err)
+			if tt.want == nil {
+				assert.NoError(t, got)
+				return
+			}
+			assert.ErrorIs(t, got, tt.want)
+			if tt.err != nil {
+				assert.ErrorContains(t, got, tt.err.Error(), "the driver error stays wrapped for logging")
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package mongodb
+
+import (
+	"regexp"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// reportField returns the document path of a ReportData field
+func reportField(field string) string {
+	return "report." + field
+}
+
+// buildFilter translates a ReportFilter into a MongoDB query document
+func buildFilter(filter application.ReportFilter) bson.D {
+	query := bson.D{}
+
+	for _, fieldFilter := range filter.FieldFilters() {
+		query = append(query, bson.E{Key: reportField(fieldFilter.Field), Value: matchValue(fieldFilter.StringFilter)})
+	}
+
+	// Reports stored before noise classification have no noise category
+	var noise bson.E
+	switch filter.Noise {
+	case application.NoiseExclude:
+		noise = bson.E{Key: reportField(domain.FieldNoiseCategory), Value: bson.D{{Key: "$in", Value: bson.A{nil, ""}}}}
+	case application.NoiseOnly:
+		noise = bson.E{Key: reportField(domain.FieldNoiseCategory), Value: bson.D{{Key: "$nin", Value: bson.A{nil, ""}}}}
+	}
+	if noise.Key != "" {
+		// A document holds each key once, so a noise category filter and the
+		// noise filter on the same field must both be conditions of an $and
+		if filter.NoiseCategory != nil {
+			query = append(query, bson.E{Key: "$and", Value: bson.A{bson.D{noise}}})
+		} else {
+			query = append(query, noise)
+		}
+	}
+
+	timeRange := bson.D{}
+	if filter.From != 0 {
+		timeRange = append(timeRange, bson.E{Key: "$gte", Value: filter.From})
+	}
+	if filter.To != 0 {
+		timeRange = append(timeRange, bson.E{Key: "$lt", Value: filter.To})
+	}
+	if len(timeRange) > 0 {
+		query = append(query, bson.E{Key: reportField(domain.FieldReportTime), Value: timeRange})
+	}
+
+	return query
+}
+
+// matchValue returns the query value for a string filter. Prefix and substring
+// matches are escaped regular expressions, so prefix matches can use an index.
+func matchValue(filter application.StringFilter) interface{} {
+	switch filter.Match {
+	case application.MatchPrefix:
+		return primitive.Regex{Pattern: "^" + regexp.QuoteMeta(filter.Value)}
+	case application.MatchContains:
+		return primitive.Regex{Pattern: regexp.QuoteMeta(filter.Value)}
+	default:
+		return filter.Value
+	}
+}
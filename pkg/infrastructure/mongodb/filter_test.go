@@ -0,0 +1,48 @@
+package mongodb
+
+import (
+	"testing"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestBuildFilterNoise(t *testing.T) {
+	unclassified := bson.D{{Key: "$in", Value: bson.A{nil, ""}}}
+	classified := bson.D{{Key: "$nin", Value: bson.A{nil, ""}}}
+
+	tests := []struct {
+		name   string
+		filter application.ReportFilter
+		want   bson.D
+	}{
+		{
+			name:   "exclude",
+			filter: application.ReportFilter{Noise: application.NoiseExclude},
+			want:   bson.D{{Key: "report.noisecategory", Value: unclassified}},
+		},
+		{
+			name:   "only",
+			filter: application.ReportFilter{Noise: application.NoiseOnly},
+			want:   bson.D{{Key: "report.noisecategory", Value: classified}},
+		},
+		{
+			name: "only in category",
+			filter: application.ReportFilter{
+				Noise:         application.NoiseOnly,
+				NoiseCategory: &application.StringFilter{Value: "extension"},
+			},
+			want: bson.D{
+				{Key: "report.noisecategory", Value: "extension"},
+				{Key: "$and", Value: bson.A{bson.D{{Key: "report.noisecategory", Value: classified}}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, buildFilter(tt.filter))
+		})
+	}
+}
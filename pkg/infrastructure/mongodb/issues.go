@@ -0,0 +1,167 @@
+package mongodb
+
+import (
+	"context"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// issueSorts maps the issue sort orders to sort documents
+var issueSorts = map[application.IssueSort]bson.D{
+	application.IssueSortCount:  {{Key: "count", Value: -1}, {Key: "_id", Value: 1}},
+	application.IssueSortRecent: {{Key: "lastseen", Value: -1}, {Key: "count", Value: -1}, {Key: "_id", Value: 1}},
+}
+
+// issuesAfter returns the filter selecting the issues listed after the cursor
+func issuesAfter(cursor *application.IssueCursor) bson.M {
+	after := bson.M{"$or": bson.A{
+		bson.M{"count": bson.M{"$lt": cursor.Count}},
+		bson.M{"count": cursor.Count, "_id": bson.M{"$gt": cursor.Fingerprint}},
+	}}
+	if cursor.Sort == application.IssueSortRecent {
+		after = bson.M{"$or": bson.A{
+			bson.M{"lastseen": bson.M{"$lt": cursor.LastSeen}},
+			bson.M{"$and": bson.A{bson.M{"lastseen": cursor.LastSeen}, after}},
+		}}
+	}
+	return after
+}
+
+// RecordIssues implements IssuesRepository.RecordIssues with one upsert per
+// issue, followed by conditional updates for the lifecycle changes
+func (r *MongoRepository) RecordIssues(ctx context.Context, issues []domain.Issue) ([]string, error) {
+	collection := r.getIssuesCollection()
+
+	models := make([]mongo.WriteModel, len(issues))
+	fingerprints := make([]string, len(issues))
+	for i, issue := range issues {
+		fingerprints[i] = issue.Fingerprint
+		models[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": issue.Fingerprint}).
+			SetUpdate(bson.M{
+				"$setOnInsert": bson.M{
+					"documentpath":       issue.DocumentPath,
+					"effectivedirective": issue.EffectiveDirective,
+					"blockedorigin":      issue.BlockedOrigin,
+					"sourcefile":         issue.SourceFile,
+					"status":             domain.IssueOpen,
+				},
+				"$min": bson.M{"firstseen": issue.FirstSeen},
+				"$max": bson.M{"lastseen": issue.LastSeen},
+				"$inc": bson.M{"count": issue.Count},
+			}).
+			SetUpsert(true)
+	}
+
+	if _, err := collection.BulkWrite(ctx, models); err != nil {
+		return nil, translateError(err)
+	}
+
+	var regressed []string
+	for _, issue := range issues {
+		result, err := collection.UpdateOne(ctx,
+			bson.M{"_id": issue.Fingerprint, "status": domain.IssueResolved, "statustime": bson.M{"$lte": issue.LastSeen}},
+			bson.M{"$set": bson.M{"status": domain.IssueRegressed, "regressedat": issue.LastSeen}},
+		)
+		if err != nil {
+			return nil, translateError(err)
+		}
+		if result.ModifiedCount > 0 {
+			regressed = append(regressed, issue.Fingerprint)
+		}
+	}
+
+	_, err := collection.UpdateMany(ctx,
+		bson.M{
+			"_id":    bson.M{"$in": fingerprints},
+			"status": domain.IssueSnoozed,
+			"$expr":  bson.M{"$gte": bson.A{"$count", "$snoozeuntil"}},
+		},
+		bson.M{"$set": bson.M{"status": domain.IssueOpen, "snoozeuntil": 0}},
+	)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return regressed, nil
+}
+
+// GetIssue implements IssuesRepository.GetIssue
+func (r *MongoRepository) GetIssue(ctx context.Context, fingerprint string) (*domain.Issue, error) {
+	var issue domain.Issue
+	err := r.getIssuesCollection().FindOne(ctx, bson.M{"_id": fingerprint}).Decode(&issue)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return &issue, nil
+}
+
+// ListIssues implements IssuesRepository.ListIssues
+func (r *MongoRepository) ListIssues(ctx context.Context, query application.IssueQuery) (*application.IssuePage, error) {
+	filter := bson.M{"status": bson.M{"$in": query.Statuses}}
+
+	page := &application.IssuePage{Issues: []domain.Issue{}}
+	if query.IncludeTotal {
+		total, err := r.getIssuesCollection().CountDocuments(ctx, filter)
+		if err != nil {
+			return nil, translateError(err)
+		}
+		page.Total = &total
+	}
+	if query.After != nil {
+		filter = bson.M{"$and": bson.A{filter, issuesAfter(query.After)}}
+	}
+
+	// Fetch one extra issue to find out whether another page follows
+	opts := options.Find().
+		SetSort(issueSorts[query.Sort]).
+		SetLimit(int64(query.Limit + 1))
+	cursor, err := r.getIssuesCollection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &page.Issues); err != nil {
+		return nil, translateError(err)
+	}
+
+	if len(page.Issues) > query.Limit {
+		page.Issues = page.Issues[:query.Limit]
+		page.Next = application.NewIssueCursor(query.Sort, page.Issues[query.Limit-1])
+	}
+
+	return page, nil
+}
+
+// SetIssueStatus implements IssuesRepository.SetIssueStatus with a pipeline
+// update, as the snooze count is relative to the stored count
+func (r *MongoRepository) SetIssueStatus(ctx context.Context, fingerprint string, change domain.IssueStatusChange) (*domain.Issue, error) {
+	var snoozeUntil interface{} = 0
+	if change.Status == domain.IssueSnoozed {
+		snoozeUntil = bson.M{"$add": bson.A{"$count", change.SnoozeCount}}
+	}
+
+	// Strings are wrapped in $literal so that notes starting with "$" are not read as field paths
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"status":      bson.M{"$literal": change.Status},
+		"statusnote":  bson.M{"$literal": change.Note},
+		"statusactor": bson.M{"$literal": change.Actor},
+		"statustime":  change.Time,
+		"snoozeuntil": snoozeUntil,
+	}}}}
+
+	var issue domain.Issue
+	err := r.getIssuesCollection().FindOneAndUpdate(ctx, bson.M{"_id": fingerprint}, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&issue)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return &issue, nil
+}
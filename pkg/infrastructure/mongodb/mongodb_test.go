@@ -0,0 +1,38 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application/repositorytest"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRepositoryContract runs the conformance suite against the MongoDB at
+// MONGODB_TEST_URI, e.g. a local container, using a fresh collection per test
+func TestRepositoryContract(t *testing.T) {
+	uri := os.Getenv("MONGODB_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGODB_TEST_URI not set")
+	}
+
+	repositorytest.Run(t, func(t *testing.T) application.Repository {
+		collection := fmt.Sprintf("reports_test_%d", time.Now().UnixNano())
+		repo, err := NewMongoRepository(uri, "csp_scout_test", collection)
+		require.NoError(t, err)
+
+		ctx := context.Background()
+		require.NoError(t, repo.EnsureIndexes(ctx))
+		t.Cleanup(func() {
+			repo.getCollection().Drop(ctx)
+			repo.getIssuesCollection().Drop(ctx)
+			repo.Close(ctx)
+		})
+
+		return repo
+	})
+}
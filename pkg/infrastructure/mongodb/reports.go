@@ -3,45 +3,99 @@ package mongodb
 import (
 	"context"
 
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
 	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // CreateReport implements ReportsRepository.CreateReport
 func (r *MongoRepository) CreateReport(ctx context.Context, report *domain.Report) error {
 	_, err := r.getCollection().InsertOne(ctx, report)
-	return err
+	return translateError(err)
+}
+
+// CreateReports implements ReportsRepository.CreateReports
+func (r *MongoRepository) CreateReports(ctx context.Context, reports []domain.Report) error {
+	documents := make([]interface{}, len(reports))
+	for i := range reports {
+		documents[i] = reports[i]
+	}
+
+	_, err := r.getCollection().InsertMany(ctx, documents)
+	return translateError(err)
 }
 
 // GetReport implements ReportsRepository.GetReport
 func (r *MongoRepository) GetReport(ctx context.Context, id string) (*domain.Report, error) {
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return nil, err
+		return nil, application.ErrInvalidID
 	}
 
 	var report domain.Report
 	err = r.getCollection().FindOne(ctx, bson.M{"_id": objectID}).Decode(&report)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 
 	return &report, nil
 }
 
-// ListReports implements ReportsRepository.ListReports
-func (r *MongoRepository) ListReports(ctx context.Context) ([]domain.Report, error) {
-	cursor, err := r.getCollection().Find(ctx, bson.M{})
+// ListReports implements ReportsRepository.ListReports using keyset pagination on report time and ID
+func (r *MongoRepository) ListReports(ctx context.Context, query application.ReportQuery) (*application.ReportPage, error) {
+	filter := buildFilter(query.Filter)
+
+	page := &application.ReportPage{}
+	if query.IncludeTotal {
+		total, err := r.getCollection().CountDocuments(ctx, filter)
+		if err != nil {
+			return nil, translateError(err)
+		}
+		page.Total = &total
+	}
+
+	direction, comparison := -1, "$lt"
+	if query.Sort == application.SortOldest {
+		direction, comparison = 1, "$gt"
+	}
+
+	if query.After != nil {
+		afterID, err := primitive.ObjectIDFromHex(query.After.ID)
+		if err != nil {
+			return nil, application.ErrInvalidCursor
+		}
+		filter = append(filter, bson.E{Key: "$or", Value: bson.A{
+			bson.D{{Key: "report.reporttime", Value: bson.D{{Key: comparison, Value: query.After.ReportTime}}}},
+			bson.D{
+				{Key: "report.reporttime", Value: query.After.ReportTime},
+				{Key: "_id", Value: bson.D{{Key: comparison, Value: afterID}}},
+			},
+		}})
+	}
+
+	// Fetch one extra report to find out whether another page follows
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "report.reporttime", Value: direction}, {Key: "_id", Value: direction}}).
+		SetLimit(int64(query.Limit) + 1)
+
+	cursor, err := r.getCollection().Find(ctx, filter, findOptions)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	defer cursor.Close(ctx)
 
-	var reports []domain.Report
+	reports := []domain.Report{}
 	if err := cursor.All(ctx, &reports); err != nil {
-		return nil, err
+		return nil, translateError(err)
+	}
+
+	if len(reports) > query.Limit {
+		reports = reports[:query.Limit]
+		page.Next = application.NewReportCursor(reports[len(reports)-1])
 	}
+	page.Reports = reports
 
-	return reports, nil
+	return page, nil
 }
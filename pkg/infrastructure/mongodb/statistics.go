@@ -2,23 +2,111 @@ package mongodb
 
 import (
 	"context"
+	"fmt"
+	"slices"
 
 	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // GetTopIPs implements StatisticsRepository.GetTopIPs
-func (r *MongoRepository) GetTopIPs(ctx context.Context) ([]application.TopIPResult, error) {
+func (r *MongoRepository) GetTopIPs(ctx context.Context, params application.StatisticsParams) ([]application.TopIPResult, error) {
+	var results []application.TopIPResult
+	if err := r.aggregateTop(ctx, "$"+reportField(domain.FieldClientIP), "ip", params, &results); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// GetTopViolatedDirectives implements StatisticsRepository.GetTopViolatedDirectives
+func (r *MongoRepository) GetTopViolatedDirectives(ctx context.Context, params application.StatisticsParams) ([]application.TopDirectiveResult, error) {
+	var results []application.TopDirectiveResult
+	if err := r.aggregateTop(ctx, "$"+reportField(domain.FieldViolatedDirective), "directive", params, &results); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// GetTopEffectiveDirectives implements StatisticsRepository.GetTopEffectiveDirectives
+func (r *MongoRepository) GetTopEffectiveDirectives(ctx context.Context, params application.StatisticsParams) ([]application.TopDirectiveResult, error) {
+	var results []application.TopDirectiveResult
+	if err := r.aggregateTop(ctx, "$"+reportField(domain.FieldEffectiveDirective), "directive", params, &results); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// GetTopBlockedURIs implements StatisticsRepository.GetTopBlockedURIs
+func (r *MongoRepository) GetTopBlockedURIs(ctx context.Context, params application.StatisticsParams) ([]application.TopBlockedURIResult, error) {
+	var group interface{} = "$" + reportField(domain.FieldBlockedUri)
+	if params.NormalizeOrigin {
+		group = originExpression(reportField(domain.FieldBlockedUri))
+	}
+
+	var results []application.TopBlockedURIResult
+	if err := r.aggregateTop(ctx, group, "blockeduri", params, &results); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// GetTopDocuments implements StatisticsRepository.GetTopDocuments
+func (r *MongoRepository) GetTopDocuments(ctx context.Context, params application.StatisticsParams) ([]application.TopDocumentResult, error) {
+	var results []application.TopDocumentResult
+	if err := r.aggregateTop(ctx, "$"+reportField(domain.FieldDocumentUri), "documenturi", params, &results); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// GetTopSourceFiles implements StatisticsRepository.GetTopSourceFiles
+func (r *MongoRepository) GetTopSourceFiles(ctx context.Context, params application.StatisticsParams) ([]application.TopSourceFileResult, error) {
+	var group interface{} = "$" + reportField(domain.FieldSourceFile)
+	if params.NormalizeOrigin {
+		group = originExpression(reportField(domain.FieldSourceFile))
+	}
+
+	var results []application.TopSourceFileResult
+	if err := r.aggregateTop(ctx, group, "sourcefile", params, &results); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// originExpression returns an aggregation expression reducing the URI at the
+// given path to its scheme and host. Values without an origin are returned unchanged.
+func originExpression(path string) bson.D {
+	return bson.D{{Key: "$let", Value: bson.D{
+		{Key: "vars", Value: bson.D{{Key: "origin", Value: bson.D{{Key: "$regexFind", Value: bson.D{
+			{Key: "input", Value: "$" + path},
+			{Key: "regex", Value: domain.OriginPattern},
+		}}}}}},
+		{Key: "in", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$$origin.match", "$" + path}}}},
+	}}}
+}
+
+// aggregateTop counts the reports matching the params' filter per value of the
+// group expression and decodes the most frequent ones, projected as
+// {<key>: value, count: n}, into results. Ties are broken by value.
+func (r *MongoRepository) aggregateTop(ctx context.Context, group interface{}, key string, params application.StatisticsParams, results interface{}) error {
 	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: buildFilter(params.Filter)}},
 		{{Key: "$group", Value: bson.D{
-			{Key: "_id", Value: "$report.clientip"},
+			{Key: "_id", Value: group},
 			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
 		}}},
-		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}}}},
-		{{Key: "$limit", Value: 20}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: params.Limit}},
 		{{Key: "$project", Value: bson.D{
-			{Key: "ip", Value: "$_id"},
+			{Key: key, Value: "$_id"},
 			{Key: "count", Value: 1},
 			{Key: "_id", Value: 0},
 		}}},
@@ -26,29 +114,38 @@ func (r *MongoRepository) GetTopIPs(ctx context.Context) ([]application.TopIPRes
 
 	cursor, err := r.getCollection().Aggregate(ctx, pipeline)
 	if err != nil {
-		return nil, err
+		return translateError(err)
 	}
 	defer cursor.Close(ctx)
 
-	var results []application.TopIPResult
-	if err := cursor.All(ctx, &results); err != nil {
-		return nil, err
+	return translateError(cursor.All(ctx, results))
+}
+
+// GetTimeSeriesCounts implements StatisticsRepository.GetTimeSeriesCounts by
+// grouping on the report time rounded down to the bucket size
+func (r *MongoRepository) GetTimeSeriesCounts(ctx context.Context, params application.TimeSeriesParams) ([]application.TimeSeriesCount, error) {
+	if params.GroupBy != "" && !slices.Contains(application.TimeSeriesGroupFields, params.GroupBy) {
+		return nil, fmt.Errorf("%w %q", application.ErrInvalidGroupBy, params.GroupBy)
 	}
 
-	return results, nil
-}
+	reportTime := "$" + reportField(domain.FieldReportTime)
+	groupKey := bson.D{{Key: "bucket", Value: bson.D{{Key: "$subtract", Value: bson.A{
+		reportTime,
+		bson.D{{Key: "$mod", Value: bson.A{reportTime, params.Bucket.Seconds()}}},
+	}}}}}
+	if params.GroupBy != "" {
+		groupKey = append(groupKey, bson.E{Key: "group", Value: "$" + reportField(params.GroupBy)})
+	}
 
-// GetTopViolatedDirectives implements StatisticsRepository.GetTopViolatedDirectives
-func (r *MongoRepository) GetTopViolatedDirectives(ctx context.Context) ([]application.TopDirectiveResult, error) {
 	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: buildFilter(params.Filter)}},
 		{{Key: "$group", Value: bson.D{
-			{Key: "_id", Value: "$report.violateddirective"},
+			{Key: "_id", Value: groupKey},
 			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
 		}}},
-		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}}}},
-		{{Key: "$limit", Value: 10}},
 		{{Key: "$project", Value: bson.D{
-			{Key: "directive", Value: "$_id"},
+			{Key: "bucket", Value: "$_id.bucket"},
+			{Key: "group", Value: "$_id.group"},
 			{Key: "count", Value: 1},
 			{Key: "_id", Value: 0},
 		}}},
@@ -56,13 +153,13 @@ func (r *MongoRepository) GetTopViolatedDirectives(ctx context.Context) ([]appli
 
 	cursor, err := r.getCollection().Aggregate(ctx, pipeline)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	defer cursor.Close(ctx)
 
-	var results []application.TopDirectiveResult
+	var results []application.TimeSeriesCount
 	if err := cursor.All(ctx, &results); err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 
 	return results, nil
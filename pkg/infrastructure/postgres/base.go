@@ -0,0 +1,36 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	// Registers the "pgx" database/sql driver
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// PostgresRepository implements the application.Repository interface on PostgreSQL
+type PostgresRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRepository creates a repository for the database at the given
+// connection URL. Connections are opened lazily; call Migrate once the
+// database is reachable to create or update the schema.
+func NewPostgresRepository(url string) (*PostgresRepository, error) {
+	db, err := sql.Open("pgx", url)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PostgresRepository{db: db}, nil
+}
+
+// Close implements the Close method required by the Repository interface
+func (r *PostgresRepository) Close(ctx context.Context) error {
+	return r.db.Close()
+}
+
+// Ping implements HealthRepository.Ping
+func (r *PostgresRepository) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
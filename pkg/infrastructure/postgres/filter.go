@@ -0,0 +1,34 @@
+package postgres
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/infrastructure/sqlstore"
+)
+
+// likeEscaper escapes the LIKE wildcards and the escape character in a literal value
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// dialect implements sqlstore.Dialect for PostgreSQL
+type dialect struct{}
+
+// Placeholder implements sqlstore.Dialect.Placeholder
+func (dialect) Placeholder(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+// Pattern implements sqlstore.Dialect.Pattern with escaped LIKE patterns, so
+// prefix matches can use an index
+func (dialect) Pattern(match application.MatchType, value string) (string, string) {
+	if match == application.MatchPrefix {
+		return "LIKE", likeEscaper.Replace(value) + "%"
+	}
+	return "LIKE", "%" + likeEscaper.Replace(value) + "%"
+}
+
+// buildFilter translates a ReportFilter into a WHERE clause
+func buildFilter(filter application.ReportFilter) *sqlstore.Where {
+	return sqlstore.BuildFilter(dialect{}, filter)
+}
@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// migrations holds the schema migrations, named <version>_<description>.sql
+//
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// migrationLockID is the advisory lock serialising migrations of concurrently starting instances
+const migrationLockID = 7_263_481_530
+
+// migration is a versioned schema change
+type migration struct {
+	version int
+	name    string
+	sql     string
+}
+
+// loadMigrations returns the embedded migrations ordered by version
+func loadMigrations() ([]migration, error) {
+	entries, err := fs.ReadDir(migrations, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	var result []migration
+	for _, entry := range entries {
+		prefix, _, found := strings.Cut(entry.Name(), "_")
+		version, err := strconv.Atoi(prefix)
+		if !found || err != nil {
+			return nil, fmt.Errorf("migration %s: name must start with a version number", entry.Name())
+		}
+
+		content, err := fs.ReadFile(migrations, "migrations/"+entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, migration{version: version, name: entry.Name(), sql: string(content)})
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].version < result[j].version })
+	for i := 1; i < len(result); i++ {
+		if result[i].version == result[i-1].version {
+			return nil, fmt.Errorf("migrations %s and %s share a version", result[i-1].name, result[i].name)
+		}
+	}
+
+	return result, nil
+}
+
+// Migrate applies the migrations not yet recorded in schema_migrations. Each
+// migration runs in its own transaction, so a failed migration leaves the
+// schema at the previous version.
+func (r *PostgresRepository) Migrate(ctx context.Context) error {
+	pending, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range pending {
+		if err := r.applyMigration(ctx, m); err != nil {
+			return fmt.Errorf("migration %s: %w", m.name, err)
+		}
+	}
+
+	return nil
+}
+
+// applyMigration runs m unless it has already been applied
+func (r *PostgresRepository) applyMigration(ctx context.Context, m migration) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", migrationLockID); err != nil {
+		return err
+	}
+
+	var applied bool
+	err = tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)", m.version).Scan(&applied)
+	if err != nil || applied {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, m.sql); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version) VALUES ($1)", m.version); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,25 @@
+CREATE TABLE reports (
+    id                 TEXT COLLATE "C" PRIMARY KEY,
+    documenturi        TEXT NOT NULL DEFAULT '',
+    referrer           TEXT NOT NULL DEFAULT '',
+    violateddirective  TEXT NOT NULL DEFAULT '',
+    effectivedirective TEXT NOT NULL DEFAULT '',
+    originalpolicy     TEXT NOT NULL DEFAULT '',
+    disposition        TEXT NOT NULL DEFAULT '',
+    blockeduri         TEXT NOT NULL DEFAULT '',
+    linenumber         INTEGER NOT NULL DEFAULT 0,
+    sourcefile         TEXT NOT NULL DEFAULT '',
+    statuscode         INTEGER NOT NULL DEFAULT 0,
+    scriptsample       TEXT NOT NULL DEFAULT '',
+    clientip           TEXT NOT NULL DEFAULT '',
+    useragent          TEXT NOT NULL DEFAULT '',
+    reporttime         BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX reports_reporttime_id ON reports (reporttime, id);
+CREATE INDEX reports_violateddirective_reporttime ON reports (violateddirective, reporttime);
+CREATE INDEX reports_effectivedirective_reporttime ON reports (effectivedirective, reporttime);
+
+-- text_pattern_ops lets prefix filters use the index as well as exact matches
+CREATE INDEX reports_documenturi_reporttime ON reports (documenturi text_pattern_ops, reporttime);
+CREATE INDEX reports_blockeduri ON reports (blockeduri text_pattern_ops);
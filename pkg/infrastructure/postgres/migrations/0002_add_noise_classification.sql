@@ -0,0 +1,3 @@
+ALTER TABLE reports
+    ADD COLUMN noisecategory TEXT NOT NULL DEFAULT '',
+    ADD COLUMN noisereason   TEXT NOT NULL DEFAULT '';
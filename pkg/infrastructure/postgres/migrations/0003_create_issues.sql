@@ -0,0 +1,24 @@
+ALTER TABLE reports
+    ADD COLUMN fingerprint TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX reports_fingerprint_reporttime ON reports (fingerprint, reporttime);
+
+CREATE TABLE issues (
+    fingerprint        TEXT COLLATE "C" PRIMARY KEY,
+    documentpath       TEXT NOT NULL DEFAULT '',
+    effectivedirective TEXT NOT NULL DEFAULT '',
+    blockedorigin      TEXT NOT NULL DEFAULT '',
+    sourcefile         TEXT NOT NULL DEFAULT '',
+    firstseen          BIGINT NOT NULL DEFAULT 0,
+    lastseen           BIGINT NOT NULL DEFAULT 0,
+    count              BIGINT NOT NULL DEFAULT 0,
+    status             TEXT NOT NULL DEFAULT 'open',
+    statusnote         TEXT NOT NULL DEFAULT '',
+    statusactor        TEXT NOT NULL DEFAULT '',
+    statustime         BIGINT NOT NULL DEFAULT 0,
+    snoozeuntil        BIGINT NOT NULL DEFAULT 0,
+    regressedat        BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX issues_count ON issues (count DESC, fingerprint);
+CREATE INDEX issues_lastseen ON issues (lastseen DESC, count DESC, fingerprint);
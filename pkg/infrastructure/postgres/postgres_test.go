@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application/repositorytest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRepository returns a repository on the database at POSTGRES_TEST_URL,
+// e.g. a local container, with a freshly migrated, empty schema
+func newTestRepository(t *testing.T) *PostgresRepository {
+	url := os.Getenv("POSTGRES_TEST_URL")
+	if url == "" {
+		t.Skip("POSTGRES_TEST_URL not set")
+	}
+
+	repo, err := NewPostgresRepository(url)
+	require.NoError(t, err)
+	t.Cleanup(func() { repo.Close(context.Background()) })
+
+	ctx := context.Background()
+	_, err = repo.db.ExecContext(ctx, "DROP TABLE IF EXISTS reports, issues, schema_migrations")
+	require.NoError(t, err)
+	require.NoError(t, repo.Migrate(ctx))
+
+	return repo
+}
+
+func TestRepositoryContract(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) application.Repository {
+		return newTestRepository(t)
+	})
+}
+
+func TestMigrate(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	// Applied migrations are skipped
+	require.NoError(t, repo.Migrate(ctx))
+
+	all, err := loadMigrations()
+	require.NoError(t, err)
+
+	var applied int
+	require.NoError(t, repo.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM schema_migrations").Scan(&applied))
+	assert.Equal(t, len(all), applied)
+}
+
+func TestLoadMigrations(t *testing.T) {
+	all, err := loadMigrations()
+	require.NoError(t, err)
+	require.NotEmpty(t, all)
+	for i, m := range all {
+		assert.Equal(t, i+1, m.version, m.name)
+	}
+}
@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/infrastructure/sqlstore"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// insertReport inserts a report with sqlstore.ReportValues
+var insertReport = sqlstore.InsertReport(dialect{})
+
+// CreateReport implements ReportsRepository.CreateReport
+func (r *PostgresRepository) CreateReport(ctx context.Context, report *domain.Report) error {
+	_, err := r.db.ExecContext(ctx, insertReport, sqlstore.ReportValues(report)...)
+	return err
+}
+
+// CreateReports implements ReportsRepository.CreateReports in a single transaction
+func (r *PostgresRepository) CreateReports(ctx context.Context, reports []domain.Report) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, insertReport)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for i := range reports {
+		if _, err := stmt.ExecContext(ctx, sqlstore.ReportValues(&reports[i])...); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetReport implements ReportsRepository.GetReport
+func (r *PostgresRepository) GetReport(ctx context.Context, id string) (*domain.Report, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, application.ErrInvalidID
+	}
+
+	row := r.db.QueryRowContext(ctx, "SELECT "+sqlstore.ReportColumns+" FROM reports WHERE id = $1", objectID.Hex())
+	report, err := sqlstore.ScanReport(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, application.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+// ListReports implements ReportsRepository.ListReports using keyset pagination on report time and ID
+func (r *PostgresRepository) ListReports(ctx context.Context, query application.ReportQuery) (*application.ReportPage, error) {
+	where := buildFilter(query.Filter)
+
+	page := &application.ReportPage{}
+	if query.IncludeTotal {
+		var total int64
+		if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM reports"+where.String(), where.Args...).Scan(&total); err != nil {
+			return nil, err
+		}
+		page.Total = &total
+	}
+
+	direction, comparison := "DESC", "<"
+	if query.Sort == application.SortOldest {
+		direction, comparison = "ASC", ">"
+	}
+
+	if query.After != nil {
+		// Object IDs are fixed-length lowercase hex, so they sort as strings in creation order
+		afterID, err := primitive.ObjectIDFromHex(query.After.ID)
+		if err != nil {
+			return nil, application.ErrInvalidCursor
+		}
+		where.Add("(reporttime, id) " + comparison + " (" + where.Arg(query.After.ReportTime) + ", " + where.Arg(afterID.Hex()) + ")")
+	}
+
+	// Fetch one extra report to find out whether another page follows
+	statement := "SELECT " + sqlstore.ReportColumns + " FROM reports" + where.String() +
+		" ORDER BY reporttime " + direction + ", id " + direction + " LIMIT " + where.Arg(query.Limit+1)
+
+	rows, err := r.db.QueryContext(ctx, statement, where.Args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports := []domain.Report{}
+	for rows.Next() {
+		report, err := sqlstore.ScanReport(rows)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(reports) > query.Limit {
+		reports = reports[:query.Limit]
+		page.Next = application.NewReportCursor(reports[len(reports)-1])
+	}
+	page.Reports = reports
+
+	return page, nil
+}
@@ -0,0 +1,119 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+	"modernc.org/sqlite"
+)
+
+// schema creates the reports and issues tables and the indexes the repository's queries rely on
+const schema = `
+CREATE TABLE IF NOT EXISTS reports (
+	id                 TEXT PRIMARY KEY,
+	documenturi        TEXT NOT NULL DEFAULT '',
+	referrer           TEXT NOT NULL DEFAULT '',
+	violateddirective  TEXT NOT NULL DEFAULT '',
+	effectivedirective TEXT NOT NULL DEFAULT '',
+	originalpolicy     TEXT NOT NULL DEFAULT '',
+	disposition        TEXT NOT NULL DEFAULT '',
+	blockeduri         TEXT NOT NULL DEFAULT '',
+	linenumber         INTEGER NOT NULL DEFAULT 0,
+	sourcefile         TEXT NOT NULL DEFAULT '',
+	statuscode         INTEGER NOT NULL DEFAULT 0,
+	scriptsample       TEXT NOT NULL DEFAULT '',
+	clientip           TEXT NOT NULL DEFAULT '',
+	useragent          TEXT NOT NULL DEFAULT '',
+	reporttime         INTEGER NOT NULL DEFAULT 0,
+	noisecategory      TEXT NOT NULL DEFAULT '',
+	noisereason        TEXT NOT NULL DEFAULT '',
+	fingerprint        TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS reports_reporttime_id ON reports (reporttime, id);
+CREATE INDEX IF NOT EXISTS reports_effectivedirective_reporttime ON reports (effectivedirective, reporttime);
+CREATE INDEX IF NOT EXISTS reports_documenturi_reporttime ON reports (documenturi, reporttime);
+CREATE INDEX IF NOT EXISTS reports_blockeduri ON reports (blockeduri);
+CREATE INDEX IF NOT EXISTS reports_fingerprint_reporttime ON reports (fingerprint, reporttime);
+
+CREATE TABLE IF NOT EXISTS issues (
+	fingerprint        TEXT PRIMARY KEY,
+	documentpath       TEXT NOT NULL DEFAULT '',
+	effectivedirective TEXT NOT NULL DEFAULT '',
+	blockedorigin      TEXT NOT NULL DEFAULT '',
+	sourcefile         TEXT NOT NULL DEFAULT '',
+	firstseen          INTEGER NOT NULL DEFAULT 0,
+	lastseen           INTEGER NOT NULL DEFAULT 0,
+	count              INTEGER NOT NULL DEFAULT 0,
+	status             TEXT NOT NULL DEFAULT 'open',
+	statusnote         TEXT NOT NULL DEFAULT '',
+	statusactor        TEXT NOT NULL DEFAULT '',
+	statustime         INTEGER NOT NULL DEFAULT 0,
+	snoozeuntil        INTEGER NOT NULL DEFAULT 0,
+	regressedat        INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS issues_count ON issues (count DESC, fingerprint);
+CREATE INDEX IF NOT EXISTS issues_lastseen ON issues (lastseen DESC, count DESC, fingerprint);
+`
+
+func init() {
+	// origin(uri) reduces a URI to its scheme and host, returning values
+	// without an origin unchanged, for grouping blocked URIs by origin
+	sqlite.MustRegisterDeterministicScalarFunction("origin", 1, func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		value, ok := args[0].(string)
+		if !ok {
+			return args[0], nil
+		}
+		if origin := domain.Origin(value); origin != "" {
+			return origin, nil
+		}
+		return value, nil
+	})
+}
+
+// SQLiteRepository implements the application.Repository interface on an embedded SQLite database
+type SQLiteRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteRepository opens the SQLite database at path, creating it and its
+// schema if necessary. Use ":memory:" for a database that lives as long as the repository.
+func NewSQLiteRepository(path string) (*SQLiteRepository, error) {
+	db, err := sql.Open("sqlite", dataSourceName(path))
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite serialises writers, so a single connection avoids busy errors and
+	// keeps an in-memory database shared by all queries
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("create schema: %w", err)
+	}
+
+	return &SQLiteRepository{db: db}, nil
+}
+
+// dataSourceName returns the driver DSN for path with the pragmas the repository expects
+func dataSourceName(path string) string {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	return path + separator + "_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+}
+
+// Close implements the Close method required by the Repository interface
+func (r *SQLiteRepository) Close(ctx context.Context) error {
+	return r.db.Close()
+}
+
+// Ping implements HealthRepository.Ping
+func (r *SQLiteRepository) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
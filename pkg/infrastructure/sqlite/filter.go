@@ -0,0 +1,35 @@
+package sqlite
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/infrastructure/sqlstore"
+)
+
+// globEscaper escapes the GLOB wildcards in a literal value
+var globEscaper = strings.NewReplacer("*", "[*]", "?", "[?]", "[", "[[]")
+
+// dialect implements sqlstore.Dialect for SQLite
+type dialect struct{}
+
+// Placeholder implements sqlstore.Dialect.Placeholder with numbered parameters,
+// so arguments may be added out of the order their placeholders appear in
+func (dialect) Placeholder(n int) string {
+	return "?" + strconv.Itoa(n)
+}
+
+// Pattern implements sqlstore.Dialect.Pattern with case-sensitive GLOB
+// patterns, so prefix matches can use an index
+func (dialect) Pattern(match application.MatchType, value string) (string, string) {
+	if match == application.MatchPrefix {
+		return "GLOB", globEscaper.Replace(value) + "*"
+	}
+	return "GLOB", "*" + globEscaper.Replace(value) + "*"
+}
+
+// buildFilter translates a ReportFilter into a WHERE clause
+func buildFilter(filter application.ReportFilter) *sqlstore.Where {
+	return sqlstore.BuildFilter(dialect{}, filter)
+}
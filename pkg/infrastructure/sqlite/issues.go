@@ -0,0 +1,141 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/infrastructure/sqlstore"
+)
+
+// recordIssue inserts an open issue or adds its occurrences to the stored one
+const recordIssue = "INSERT INTO issues (fingerprint, documentpath, effectivedirective, blockedorigin, sourcefile, firstseen, lastseen, count) " +
+	"VALUES (?, ?, ?, ?, ?, ?, ?, ?) " +
+	"ON CONFLICT (fingerprint) DO UPDATE SET firstseen = min(issues.firstseen, excluded.firstseen), " +
+	"lastseen = max(issues.lastseen, excluded.lastseen), count = issues.count + excluded.count"
+
+// regressIssue reopens a resolved issue that was seen after its resolution
+const regressIssue = "UPDATE issues SET status = 'regressed', regressedat = ? " +
+	"WHERE fingerprint = ? AND status = 'resolved' AND statustime <= ?"
+
+// wakeIssue reopens a snoozed issue that reached its count
+const wakeIssue = "UPDATE issues SET status = 'open', snoozeuntil = 0 " +
+	"WHERE fingerprint = ? AND status = 'snoozed' AND count >= snoozeuntil"
+
+// setIssueStatus applies a triage decision
+const setIssueStatus = "UPDATE issues SET status = ?, statusnote = ?, statusactor = ?, statustime = ?, " +
+	"snoozeuntil = CASE WHEN ? > 0 THEN count + ? ELSE 0 END WHERE fingerprint = ? RETURNING " + sqlstore.IssueColumns
+
+// RecordIssues implements IssuesRepository.RecordIssues in a single transaction
+func (r *SQLiteRepository) RecordIssues(ctx context.Context, issues []domain.Issue) ([]string, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var regressed []string
+	for _, issue := range issues {
+		_, err := tx.ExecContext(ctx, recordIssue, issue.Fingerprint, issue.DocumentPath, issue.EffectiveDirective,
+			issue.BlockedOrigin, issue.SourceFile, issue.FirstSeen, issue.LastSeen, issue.Count)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := tx.ExecContext(ctx, regressIssue, issue.LastSeen, issue.Fingerprint, issue.LastSeen)
+		if err != nil {
+			return nil, err
+		}
+		if n, err := result.RowsAffected(); err != nil {
+			return nil, err
+		} else if n > 0 {
+			regressed = append(regressed, issue.Fingerprint)
+		}
+
+		if _, err := tx.ExecContext(ctx, wakeIssue, issue.Fingerprint); err != nil {
+			return nil, err
+		}
+	}
+
+	return regressed, tx.Commit()
+}
+
+// GetIssue implements IssuesRepository.GetIssue
+func (r *SQLiteRepository) GetIssue(ctx context.Context, fingerprint string) (*domain.Issue, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT "+sqlstore.IssueColumns+" FROM issues WHERE fingerprint = ?", fingerprint)
+	issue, err := sqlstore.ScanIssue(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, application.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &issue, nil
+}
+
+// ListIssues implements IssuesRepository.ListIssues
+func (r *SQLiteRepository) ListIssues(ctx context.Context, query application.IssueQuery) (*application.IssuePage, error) {
+	where := sqlstore.NewWhere(dialect{})
+	where.Add(sqlstore.StatusesIn(where, query.Statuses))
+
+	page := &application.IssuePage{Issues: []domain.Issue{}}
+	if query.IncludeTotal {
+		var total int64
+		if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM issues"+where.String(), where.Args...).Scan(&total); err != nil {
+			return nil, err
+		}
+		page.Total = &total
+	}
+	if query.After != nil {
+		where.Add(sqlstore.IssuesAfter(where, query.After))
+	}
+
+	// Fetch one extra issue to find out whether another page follows
+	rows, err := r.db.QueryContext(ctx, "SELECT "+sqlstore.IssueColumns+" FROM issues"+where.String()+sqlstore.IssueOrders[query.Sort]+
+		" LIMIT "+where.Arg(query.Limit+1), where.Args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		issue, err := sqlstore.ScanIssue(rows)
+		if err != nil {
+			return nil, err
+		}
+		page.Issues = append(page.Issues, issue)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(page.Issues) > query.Limit {
+		page.Issues = page.Issues[:query.Limit]
+		page.Next = application.NewIssueCursor(query.Sort, page.Issues[query.Limit-1])
+	}
+
+	return page, nil
+}
+
+// SetIssueStatus implements IssuesRepository.SetIssueStatus
+func (r *SQLiteRepository) SetIssueStatus(ctx context.Context, fingerprint string, change domain.IssueStatusChange) (*domain.Issue, error) {
+	snoozeCount := 0
+	if change.Status == domain.IssueSnoozed {
+		snoozeCount = change.SnoozeCount
+	}
+
+	row := r.db.QueryRowContext(ctx, setIssueStatus, change.Status, change.Note, change.Actor, change.Time,
+		snoozeCount, snoozeCount, fingerprint)
+	issue, err := sqlstore.ScanIssue(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, application.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &issue, nil
+}
@@ -0,0 +1,43 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application/repositorytest"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRepositoryContract(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) application.Repository {
+		repo, err := NewSQLiteRepository(":memory:")
+		require.NoError(t, err)
+		t.Cleanup(func() { repo.Close(context.Background()) })
+		return repo
+	})
+}
+
+func TestReportsPersistAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reports.db")
+	ctx := context.Background()
+
+	repo, err := NewSQLiteRepository(path)
+	require.NoError(t, err)
+	report := &domain.Report{ID: primitive.NewObjectID(), Report: domain.ReportData{DocumentUri: "https://example.com/"}}
+	require.NoError(t, repo.CreateReport(ctx, report))
+	require.NoError(t, repo.Close(ctx))
+
+	// Reopening an existing database keeps its schema and reports
+	repo, err = NewSQLiteRepository(path)
+	require.NoError(t, err)
+	defer repo.Close(ctx)
+
+	found, err := repo.GetReport(ctx, report.ID.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, report, found)
+}
@@ -0,0 +1,161 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/infrastructure/sqlstore"
+)
+
+// GetTopIPs implements StatisticsRepository.GetTopIPs
+func (r *SQLiteRepository) GetTopIPs(ctx context.Context, params application.StatisticsParams) ([]application.TopIPResult, error) {
+	var results []application.TopIPResult
+	err := r.aggregateTop(ctx, domain.FieldClientIP, params, func(value string, count int) {
+		results = append(results, application.TopIPResult{IP: value, Count: count})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// GetTopViolatedDirectives implements StatisticsRepository.GetTopViolatedDirectives
+func (r *SQLiteRepository) GetTopViolatedDirectives(ctx context.Context, params application.StatisticsParams) ([]application.TopDirectiveResult, error) {
+	var results []application.TopDirectiveResult
+	err := r.aggregateTop(ctx, domain.FieldViolatedDirective, params, func(value string, count int) {
+		results = append(results, application.TopDirectiveResult{Directive: value, Count: count})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// GetTopEffectiveDirectives implements StatisticsRepository.GetTopEffectiveDirectives
+func (r *SQLiteRepository) GetTopEffectiveDirectives(ctx context.Context, params application.StatisticsParams) ([]application.TopDirectiveResult, error) {
+	var results []application.TopDirectiveResult
+	err := r.aggregateTop(ctx, domain.FieldEffectiveDirective, params, func(value string, count int) {
+		results = append(results, application.TopDirectiveResult{Directive: value, Count: count})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// GetTopBlockedURIs implements StatisticsRepository.GetTopBlockedURIs
+func (r *SQLiteRepository) GetTopBlockedURIs(ctx context.Context, params application.StatisticsParams) ([]application.TopBlockedURIResult, error) {
+	group := domain.FieldBlockedUri
+	if params.NormalizeOrigin {
+		group = "origin(" + group + ")"
+	}
+
+	var results []application.TopBlockedURIResult
+	err := r.aggregateTop(ctx, group, params, func(value string, count int) {
+		results = append(results, application.TopBlockedURIResult{BlockedURI: value, Count: count})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// GetTopDocuments implements StatisticsRepository.GetTopDocuments
+func (r *SQLiteRepository) GetTopDocuments(ctx context.Context, params application.StatisticsParams) ([]application.TopDocumentResult, error) {
+	var results []application.TopDocumentResult
+	err := r.aggregateTop(ctx, domain.FieldDocumentUri, params, func(value string, count int) {
+		results = append(results, application.TopDocumentResult{DocumentURI: value, Count: count})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// GetTopSourceFiles implements StatisticsRepository.GetTopSourceFiles
+func (r *SQLiteRepository) GetTopSourceFiles(ctx context.Context, params application.StatisticsParams) ([]application.TopSourceFileResult, error) {
+	group := domain.FieldSourceFile
+	if params.NormalizeOrigin {
+		group = "origin(" + group + ")"
+	}
+
+	var results []application.TopSourceFileResult
+	err := r.aggregateTop(ctx, group, params, func(value string, count int) {
+		results = append(results, application.TopSourceFileResult{SourceFile: value, Count: count})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// aggregateTop counts the reports matching the params' filter per value of the
+// group expression and passes the most frequent ones to add. Ties are broken by value.
+func (r *SQLiteRepository) aggregateTop(ctx context.Context, group string, params application.StatisticsParams, add func(value string, count int)) error {
+	where := buildFilter(params.Filter)
+	statement := "SELECT " + group + " AS value, COUNT(*) AS count FROM reports" + where.String() +
+		" GROUP BY value ORDER BY count DESC, value ASC LIMIT " + where.Arg(params.Limit)
+
+	rows, err := r.db.QueryContext(ctx, statement, where.Args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var value string
+		var count int
+		if err := rows.Scan(&value, &count); err != nil {
+			return err
+		}
+		add(value, count)
+	}
+
+	return rows.Err()
+}
+
+// GetTimeSeriesCounts implements StatisticsRepository.GetTimeSeriesCounts by
+// grouping on the report time rounded down to the bucket size
+func (r *SQLiteRepository) GetTimeSeriesCounts(ctx context.Context, params application.TimeSeriesParams) ([]application.TimeSeriesCount, error) {
+	where := buildFilter(params.Filter)
+
+	group := "''"
+	if params.GroupBy != "" {
+		column, ok := sqlstore.TimeSeriesGroupColumns[params.GroupBy]
+		if !ok {
+			return nil, fmt.Errorf("%w %q", application.ErrInvalidGroupBy, params.GroupBy)
+		}
+		group = column
+	}
+	size := where.Arg(params.Bucket.Seconds())
+	statement := "SELECT reporttime - reporttime % " + size + " AS bucket, " + group + " AS grp, COUNT(*) FROM reports" +
+		where.String() + " GROUP BY bucket, grp"
+
+	rows, err := r.db.QueryContext(ctx, statement, where.Args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []application.TimeSeriesCount
+	for rows.Next() {
+		var count application.TimeSeriesCount
+		if err := rows.Scan(&count.Bucket, &count.Group, &count.Count); err != nil {
+			return nil, err
+		}
+		results = append(results, count)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
@@ -0,0 +1,96 @@
+// Package sqlstore holds the SQL the database/sql storage backends share: the
+// report and issue columns and the translation of report filters into WHERE
+// clauses. A Dialect supplies what differs between the databases.
+package sqlstore
+
+import (
+	"strings"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+)
+
+// Dialect describes how a database spells placeholders and pattern matches
+type Dialect interface {
+	// Placeholder returns the placeholder of the n-th argument, counted from 1
+	Placeholder(n int) string
+	// Pattern returns the operator and the pattern matching values that start
+	// with or contain value, for MatchPrefix and MatchContains
+	Pattern(match application.MatchType, value string) (operator, pattern string)
+}
+
+// Where accumulates the conditions and arguments of a WHERE clause
+type Where struct {
+	dialect    Dialect
+	conditions []string
+	Args       []interface{}
+}
+
+// NewWhere returns an empty WHERE clause for dialect
+func NewWhere(dialect Dialect) *Where {
+	return &Where{dialect: dialect}
+}
+
+// Arg adds an argument and returns its placeholder
+func (w *Where) Arg(value interface{}) string {
+	w.Args = append(w.Args, value)
+	return w.dialect.Placeholder(len(w.Args))
+}
+
+// Add appends a condition
+func (w *Where) Add(condition string) {
+	w.conditions = append(w.conditions, condition)
+}
+
+// String returns the WHERE clause, or an empty string without conditions
+func (w *Where) String() string {
+	if len(w.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(w.conditions, " AND ")
+}
+
+// BuildFilter translates a ReportFilter into a WHERE clause. Report fields are
+// stored in columns of the same name.
+func BuildFilter(dialect Dialect, filter application.ReportFilter) *Where {
+	where := NewWhere(dialect)
+
+	for _, fieldFilter := range filter.FieldFilters() {
+		where.Add(matchCondition(where, fieldFilter.Field, fieldFilter.StringFilter))
+	}
+
+	switch filter.Noise {
+	case application.NoiseExclude:
+		where.Add(domain.FieldNoiseCategory + " = ''")
+	case application.NoiseOnly:
+		where.Add(domain.FieldNoiseCategory + " <> ''")
+	}
+
+	if filter.From != 0 {
+		where.Add(domain.FieldReportTime + " >= " + where.Arg(filter.From))
+	}
+	if filter.To != 0 {
+		where.Add(domain.FieldReportTime + " < " + where.Arg(filter.To))
+	}
+
+	return where
+}
+
+// matchCondition returns the condition for a string filter on a column
+func matchCondition(where *Where, column string, filter application.StringFilter) string {
+	switch filter.Match {
+	case application.MatchPrefix, application.MatchContains:
+		operator, pattern := where.dialect.Pattern(filter.Match, filter.Value)
+		return column + " " + operator + " " + where.Arg(pattern)
+	default:
+		return column + " = " + where.Arg(filter.Value)
+	}
+}
+
+// TimeSeriesGroupColumns maps the fields a time series can be grouped by to
+// their columns, so that no caller-supplied text reaches the SQL
+var TimeSeriesGroupColumns = map[string]string{
+	domain.FieldViolatedDirective:  "violateddirective",
+	domain.FieldEffectiveDirective: "effectivedirective",
+	domain.FieldDisposition:        "disposition",
+}
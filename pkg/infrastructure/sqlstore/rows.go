@@ -0,0 +1,100 @@
+package sqlstore
+
+import (
+	"strings"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Scanner is implemented by *sql.Row and *sql.Rows
+type Scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// ReportColumns lists the columns of the reports table in scan and insert order
+const ReportColumns = "id, documenturi, referrer, violateddirective, effectivedirective, originalpolicy, " +
+	"disposition, blockeduri, linenumber, sourcefile, statuscode, scriptsample, clientip, useragent, reporttime, " +
+	"noisecategory, noisereason, fingerprint"
+
+// InsertReport returns the statement inserting a report with ReportValues
+func InsertReport(dialect Dialect) string {
+	count := strings.Count(ReportColumns, ",") + 1
+	placeholders := make([]string, count)
+	for i := range placeholders {
+		placeholders[i] = dialect.Placeholder(i + 1)
+	}
+	return "INSERT INTO reports (" + ReportColumns + ") VALUES (" + strings.Join(placeholders, ", ") + ")"
+}
+
+// ReportValues returns the column values of a report in ReportColumns order
+func ReportValues(report *domain.Report) []interface{} {
+	data := report.Report
+	return []interface{}{
+		report.ID.Hex(), data.DocumentUri, data.Referrer, data.ViolatedDirective, data.EffectiveDirective,
+		data.OriginalPolicy, data.Disposition, data.BlockedUri, data.LineNumber, data.SourceFile,
+		data.StatusCode, data.ScriptSample, data.ClientIP, data.UserAgent, data.ReportTime,
+		data.NoiseCategory, data.NoiseReason, data.Fingerprint,
+	}
+}
+
+// ScanReport reads a row selected with ReportColumns
+func ScanReport(row Scanner) (domain.Report, error) {
+	var report domain.Report
+	var id string
+	data := &report.Report
+	err := row.Scan(
+		&id, &data.DocumentUri, &data.Referrer, &data.ViolatedDirective, &data.EffectiveDirective,
+		&data.OriginalPolicy, &data.Disposition, &data.BlockedUri, &data.LineNumber, &data.SourceFile,
+		&data.StatusCode, &data.ScriptSample, &data.ClientIP, &data.UserAgent, &data.ReportTime,
+		&data.NoiseCategory, &data.NoiseReason, &data.Fingerprint,
+	)
+	if err != nil {
+		return report, err
+	}
+
+	report.ID, err = primitive.ObjectIDFromHex(id)
+	return report, err
+}
+
+// IssueColumns lists the columns of the issues table in scan order
+const IssueColumns = "fingerprint, documentpath, effectivedirective, blockedorigin, sourcefile, firstseen, lastseen, count, " +
+	"status, statusnote, statusactor, statustime, snoozeuntil, regressedat"
+
+// IssueOrders maps the issue sort orders to ORDER BY clauses
+var IssueOrders = map[application.IssueSort]string{
+	application.IssueSortCount:  " ORDER BY count DESC, fingerprint ASC",
+	application.IssueSortRecent: " ORDER BY lastseen DESC, count DESC, fingerprint ASC",
+}
+
+// ScanIssue reads a row selected with IssueColumns
+func ScanIssue(row Scanner) (domain.Issue, error) {
+	var issue domain.Issue
+	err := row.Scan(
+		&issue.Fingerprint, &issue.DocumentPath, &issue.EffectiveDirective, &issue.BlockedOrigin,
+		&issue.SourceFile, &issue.FirstSeen, &issue.LastSeen, &issue.Count,
+		&issue.Status, &issue.StatusNote, &issue.StatusActor, &issue.StatusTime, &issue.SnoozeUntil, &issue.RegressedAt,
+	)
+	return issue, err
+}
+
+// IssuesAfter returns the condition selecting the issues listed after the cursor
+func IssuesAfter(where *Where, cursor *application.IssueCursor) string {
+	count, fingerprint := where.Arg(cursor.Count), where.Arg(cursor.Fingerprint)
+	condition := "(count < " + count + " OR (count = " + count + " AND fingerprint > " + fingerprint + "))"
+	if cursor.Sort == application.IssueSortRecent {
+		lastSeen := where.Arg(cursor.LastSeen)
+		condition = "(lastseen < " + lastSeen + " OR (lastseen = " + lastSeen + " AND " + condition + "))"
+	}
+	return condition
+}
+
+// StatusesIn returns the condition selecting issues with any of the statuses
+func StatusesIn(where *Where, statuses []domain.IssueStatus) string {
+	placeholders := make([]string, len(statuses))
+	for i, status := range statuses {
+		placeholders[i] = where.Arg(string(status))
+	}
+	return "status IN (" + strings.Join(placeholders, ", ") + ")"
+}
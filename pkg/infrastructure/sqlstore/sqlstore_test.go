@@ -0,0 +1,66 @@
+package sqlstore
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// testDialect numbers placeholders like PostgreSQL and matches with LIKE without escaping
+type testDialect struct{}
+
+func (testDialect) Placeholder(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+func (testDialect) Pattern(match application.MatchType, value string) (string, string) {
+	if match == application.MatchPrefix {
+		return "LIKE", value + "%"
+	}
+	return "LIKE", "%" + value + "%"
+}
+
+func TestBuildFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter application.ReportFilter
+		clause string
+		args   []interface{}
+	}{
+		{"empty", application.ReportFilter{}, "", nil},
+		{
+			"fields and time range",
+			application.ReportFilter{
+				DocumentUri:       &application.StringFilter{Value: "https://example.com/", Match: application.MatchPrefix},
+				ViolatedDirective: &application.StringFilter{Value: "script-src", Match: application.MatchExact},
+				BlockedUri:        &application.StringFilter{Value: "cdn", Match: application.MatchContains},
+				Noise:             application.NoiseExclude,
+				From:              100,
+				To:                200,
+			},
+			" WHERE documenturi LIKE $1 AND violateddirective = $2 AND blockeduri LIKE $3 AND noisecategory = ''" +
+				" AND reporttime >= $4 AND reporttime < $5",
+			[]interface{}{"https://example.com/%", "script-src", "%cdn%", 100, 200},
+		},
+		{"only noise", application.ReportFilter{Noise: application.NoiseOnly}, " WHERE noisecategory <> ''", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where := BuildFilter(testDialect{}, tt.filter)
+			assert.Equal(t, tt.clause, where.String())
+			assert.Equal(t, tt.args, where.Args)
+		})
+	}
+}
+
+func TestInsertReport(t *testing.T) {
+	statement := InsertReport(testDialect{})
+	columns := strings.Count(ReportColumns, ",") + 1
+	assert.Len(t, ReportValues(&domain.Report{}), columns)
+	assert.True(t, strings.HasSuffix(statement, "$"+strconv.Itoa(columns)+")"))
+}
@@ -0,0 +1,144 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+)
+
+// defaultQueueSize is the number of events waiting for delivery beyond which
+// further events are dropped
+const defaultQueueSize = 100
+
+// delivery is a queued issue event with its JSON body
+type delivery struct {
+	event application.IssueEvent
+	body  []byte
+}
+
+// Notifier posts issue events as JSON to a webhook URL. Events are queued and
+// delivered one at a time by a single worker until the notifier is closed.
+type Notifier struct {
+	url     string
+	client  *http.Client
+	onError func(err error)
+
+	mu     sync.Mutex
+	closed bool
+	queue  chan delivery
+	done   chan struct{}
+	// ctx is cancelled when Close gives up on the queued deliveries
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewNotifier creates a notifier posting to url, giving up on a delivery after timeout
+func NewNotifier(url string, timeout time.Duration) *Notifier {
+	return newNotifier(url, timeout, defaultQueueSize)
+}
+
+func newNotifier(url string, timeout time.Duration, queueSize int) *Notifier {
+	ctx, cancel := context.WithCancel(context.Background())
+	n := &Notifier{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+		onError: func(err error) {
+			log.Printf("Warning: failed to deliver issue event: %v", err)
+		},
+		queue:  make(chan delivery, queueSize),
+		done:   make(chan struct{}),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	go n.run()
+	return n
+}
+
+// HandleIssueEvent implements application.IssueEventHandler. The event is
+// queued so ingestion is not held up by the webhook. Events arriving while the
+// queue is full or after Close are dropped; failed deliveries are logged and
+// not retried.
+func (n *Notifier) HandleIssueEvent(ctx context.Context, event application.IssueEvent) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		n.onError(err)
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.closed {
+		n.onError(fmt.Errorf("%s for %s: notifier is closed", event.Type, event.Issue.Fingerprint))
+		return
+	}
+	select {
+	case n.queue <- delivery{event: event, body: body}:
+	default:
+		n.onError(fmt.Errorf("%s for %s: queue is full", event.Type, event.Issue.Fingerprint))
+	}
+}
+
+// Close stops accepting events and waits for the queued ones to be delivered.
+// When ctx expires first, the remaining deliveries are abandoned.
+func (n *Notifier) Close(ctx context.Context) error {
+	n.mu.Lock()
+	if !n.closed {
+		n.closed = true
+		close(n.queue)
+	}
+	n.mu.Unlock()
+
+	select {
+	case <-n.done:
+		return nil
+	case <-ctx.Done():
+		n.cancel()
+		<-n.done
+		return fmt.Errorf("abandoned %d queued issue events: %w", len(n.queue), ctx.Err())
+	}
+}
+
+// run delivers the queued events until the queue is closed and drained or
+// Close gives up
+func (n *Notifier) run() {
+	defer close(n.done)
+	for {
+		select {
+		case <-n.ctx.Done():
+			return
+		case d, ok := <-n.queue:
+			if !ok {
+				return
+			}
+			if err := n.post(n.ctx, d.body); err != nil {
+				n.onError(fmt.Errorf("%s for %s: %w", d.event.Type, d.event.Issue.Fingerprint, err))
+			}
+		}
+	}
+}
+
+func (n *Notifier) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
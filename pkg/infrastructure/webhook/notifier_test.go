@@ -0,0 +1,137 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifier(t *testing.T) {
+	received := make(chan application.IssueEvent, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var event application.IssueEvent
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		received <- event
+	}))
+	defer server.Close()
+
+	event := application.IssueEvent{
+		Type:  application.IssueEventRegressed,
+		Issue: domain.Issue{Fingerprint: "0123456789abcdef0123456789abcdef", Status: domain.IssueRegressed, Count: 3},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	NewNotifier(server.URL, time.Second).HandleIssueEvent(ctx, event)
+	// Delivery outlives the request that caused it
+	cancel()
+
+	select {
+	case got := <-received:
+		assert.Equal(t, event, got)
+	case <-time.After(5 * time.Second):
+		t.Fatal("event not delivered")
+	}
+}
+
+func TestNotifierFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	failed := make(chan error, 1)
+	notifier := NewNotifier(server.URL, time.Second)
+	notifier.onError = func(err error) { failed <- err }
+	notifier.HandleIssueEvent(context.Background(), application.IssueEvent{Type: application.IssueEventRegressed})
+
+	select {
+	case err := <-failed:
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "502 Bad Gateway")
+	case <-time.After(5 * time.Second):
+		t.Fatal("failure not reported")
+	}
+}
+
+func TestNotifierClose(t *testing.T) {
+	release := make(chan struct{})
+	var delivered atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		delivered.Add(1)
+	}))
+	defer server.Close()
+
+	var errs []error
+	var mu sync.Mutex
+	notifier := newNotifier(server.URL, 5*time.Second, 2)
+	notifier.onError = func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+	}
+
+	// One event is delivered while two wait in the queue; the queue is full after that
+	event := application.IssueEvent{Type: application.IssueEventRegressed, Issue: domain.Issue{Fingerprint: "0123456789abcdef0123456789abcdef"}}
+	notifier.HandleIssueEvent(context.Background(), event)
+	require.Eventually(t, func() bool { return len(notifier.queue) == 0 }, 5*time.Second, time.Millisecond)
+	for range 3 {
+		notifier.HandleIssueEvent(context.Background(), event)
+	}
+
+	// Close waits for the queued events
+	closed := make(chan error, 1)
+	go func() {
+		closed <- notifier.Close(context.Background())
+	}()
+	close(release)
+
+	select {
+	case err := <-closed:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("queued events not delivered on close")
+	}
+	assert.Equal(t, int32(3), delivered.Load())
+
+	// Events after closing are dropped
+	notifier.HandleIssueEvent(context.Background(), event)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, errs, 2)
+	assert.ErrorContains(t, errs[0], "queue is full")
+	assert.ErrorContains(t, errs[1], "notifier is closed")
+}
+
+func TestNotifierCloseTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	notifier := newNotifier(server.URL, 5*time.Second, 2)
+	notifier.onError = func(err error) {}
+	for range 3 {
+		notifier.HandleIssueEvent(context.Background(), application.IssueEvent{Type: application.IssueEventRegressed})
+	}
+
+	// The stuck delivery and the queued one are abandoned
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := notifier.Close(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -13,34 +13,57 @@ const (
 	V2 APIVersion = "v2"
 )
 
-// RegisterRoutes configures all API routes with versioning
-func RegisterRoutes(router *gin.Engine, service *application.Service) {
+// Limits bounds what a single request may submit
+type Limits struct {
+	// MaxBatchReports is the maximum number of entries in a Reporting API batch, 0 for no limit
+	MaxBatchReports int
+	// Ingest are the handlers run before the report submissions, such as a rate limit
+	Ingest []gin.HandlerFunc
+}
+
+// IngestPaths are the routes accepting report submissions with POST
+var IngestPaths = []string{
+	"/api/v1/reports",
+	"/api/v1/reports/csp-report",
+	"/api/v1/reports/reporting-api",
+	"/api/v2/reports",
+}
+
+// RegisterRoutes configures all API routes with versioning. draining reports
+// whether the server is shutting down, which fails the readiness probe.
+func RegisterRoutes(router *gin.Engine, service *application.Service, limits Limits, draining func() bool) {
+	// Register liveness and readiness probes
+	setupHealthRoutes(router, service.Health, draining)
+
 	// Register V1 routes
 	apiV1 := router.Group("/api/v1")
-	setupV1Routes(apiV1, service)
+	setupV1Routes(apiV1, service, limits)
 
-	// Register V2 routes when needed
-	// apiV2 := router.Group("/api/v2")
-	// setupV2Routes(apiV2, service)
+	// Register V2 routes
+	apiV2 := router.Group("/api/v2")
+	setupV2Routes(apiV2, service, limits)
 }
 
 // setupV1Routes configures all V1 API routes
-func setupV1Routes(router *gin.RouterGroup, service *application.Service) {
+func setupV1Routes(router *gin.RouterGroup, service *application.Service, limits Limits) {
 	// Reports CRUD routes
-	setupReportRoutesV1(router, service.Reports)
+	setupReportRoutesV1(router, service.Reports, limits)
 
 	// Statistics routes
 	setupStatisticsRoutesV1(router, service.Statistics)
+
+	// Policy recommendation routes
+	setupPolicyRoutesV1(router, service.Policies)
+
+	// Issue routes
+	setupIssueRoutesV1(router, service.Issues)
 }
 
 // setupV2Routes configures all V2 API routes
-// Uncomment and implement when V2 is needed
-/*
-func setupV2Routes(router *gin.RouterGroup, service *application.Service) {
+func setupV2Routes(router *gin.RouterGroup, service *application.Service, limits Limits) {
 	// Reports CRUD routes
-	setupReportRoutesV2(router, service.Reports)
+	setupReportRoutesV2(router, service.Reports, limits)
 
 	// Statistics routes
 	setupStatisticsRoutesV2(router, service.Statistics)
 }
-*/
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/gin-gonic/gin"
+)
+
+// readinessTimeout bounds the repository check of a readiness probe
+const readinessTimeout = 2 * time.Second
+
+type HealthHandler struct {
+	service  application.HealthService
+	draining func() bool
+}
+
+// NewHealthHandler creates a health handler. draining reports whether the
+// server is shutting down, so that load balancers stop routing to it.
+func NewHealthHandler(service application.HealthService, draining func() bool) *HealthHandler {
+	return &HealthHandler{
+		service:  service,
+		draining: draining,
+	}
+}
+
+// Health Routes, unversioned for orchestrator probes
+func setupHealthRoutes(router gin.IRouter, service application.HealthService, draining func() bool) {
+	handler := NewHealthHandler(service, draining)
+	router.GET("/healthz", handler.Liveness)
+	router.GET("/readyz", handler.Readiness)
+}
+
+// Liveness reports that the process is running and able to serve requests
+func (h *HealthHandler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readiness reports whether the server is not draining and the repository is reachable
+func (h *HealthHandler) Readiness(c *gin.Context) {
+	if h.draining() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "error": "server is shutting down"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	if err := h.service.CheckReadiness(ctx); err != nil {
+		_ = c.Error(err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "error": "repository unreachable"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockHealthService is a mock implementation of HealthService
+type MockHealthService struct {
+	mock.Mock
+}
+
+func (m *MockHealthService) CheckReadiness(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func setupHealthTestRouter(service *MockHealthService, draining bool) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	setupHealthRoutes(router, service, func() bool { return draining })
+	return router
+}
+
+func TestHealthProbes(t *testing.T) {
+	tests := []struct {
+		name           string
+		endpoint       string
+		setupMock      func(*MockHealthService)
+		draining       bool
+		expectedStatus int
+		expectedBody   gin.H
+	}{
+		{
+			name:           "Liveness",
+			endpoint:       "/healthz",
+			setupMock:      func(m *MockHealthService) {},
+			expectedStatus: http.StatusOK,
+			expectedBody:   gin.H{"status": "ok"},
+		},
+		{
+			name:     "Ready",
+			endpoint: "/readyz",
+			setupMock: func(m *MockHealthService) {
+				m.On("CheckReadiness", mock.Anything).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   gin.H{"status": "ok"},
+		},
+		{
+			name:     "Repository Unreachable",
+			endpoint: "/readyz",
+			setupMock: func(m *MockHealthService) {
+				m.On("CheckReadiness", mock.Anything).Return(errors.New("server selection error: context deadline exceeded"))
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   gin.H{"status": "unavailable", "error": "repository unreachable"},
+		},
+		{
+			name:           "Draining",
+			endpoint:       "/readyz",
+			setupMock:      func(m *MockHealthService) {},
+			draining:       true,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   gin.H{"status": "unavailable", "error": "server is shutting down"},
+		},
+		{
+			name:           "Liveness While Draining",
+			endpoint:       "/healthz",
+			setupMock:      func(m *MockHealthService) {},
+			draining:       true,
+			expectedStatus: http.StatusOK,
+			expectedBody:   gin.H{"status": "ok"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockHealthService)
+			tt.setupMock(mockService)
+			router := setupHealthTestRouter(mockService, tt.draining)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", tt.endpoint, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			expectedJSON, _ := json.Marshal(tt.expectedBody)
+			assert.JSONEq(t, string(expectedJSON), w.Body.String())
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+	"github.com/gin-gonic/gin"
+)
+
+type IssuesHandler struct {
+	service application.IssuesService
+}
+
+func NewIssuesHandler(service application.IssuesService) *IssuesHandler {
+	return &IssuesHandler{
+		service: service,
+	}
+}
+
+// V1 Routes
+func setupIssueRoutesV1(router *gin.RouterGroup, service application.IssuesService) {
+	handler := NewIssuesHandler(service)
+	issues := router.Group("/issues")
+	{
+		issues.GET("", handler.ListV1)
+		issues.GET("/:fingerprint", handler.GetV1)
+		issues.GET("/:fingerprint/reports", handler.ListReportsV1)
+		issues.POST("/:fingerprint/status", handler.UpdateStatusV1)
+	}
+}
+
+// parseIssueQuery reads the issue status filter, the sort order, the page size
+// and total, and the cursor of the page to continue after, as returned in the
+// previous page's Link header
+func parseIssueQuery(c *gin.Context) (application.IssueQuery, error) {
+	var query application.IssueQuery
+
+	if value := c.Query("status"); value != "" {
+		statuses, err := parseIssueStatuses(value)
+		if err != nil {
+			return query, err
+		}
+		query.Statuses = statuses
+	}
+
+	switch sort := application.IssueSort(c.Query("sort")); sort {
+	case "", application.IssueSortCount, application.IssueSortRecent:
+		query.Sort = sort
+	default:
+		return query, fmt.Errorf("invalid sort %q: must be %q or %q", sort, application.IssueSortCount, application.IssueSortRecent)
+	}
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			return query, fmt.Errorf("invalid limit %q: must be a positive integer", value)
+		}
+		query.Limit = limit
+	}
+
+	if value := c.Query("cursor"); value != "" {
+		cursor, err := application.DecodeIssueCursor(value)
+		if err != nil {
+			return query, err
+		}
+		query.After = cursor
+	}
+
+	if value := c.Query("total"); value != "" {
+		total, err := strconv.ParseBool(value)
+		if err != nil {
+			return query, fmt.Errorf("invalid total %q: must be a boolean", value)
+		}
+		query.IncludeTotal = total
+	}
+
+	return query, nil
+}
+
+// parseIssueStatuses reads a comma-separated list of issue statuses, where
+// "all" selects every status
+func parseIssueStatuses(value string) ([]domain.IssueStatus, error) {
+	if value == "all" {
+		return domain.IssueStatuses, nil
+	}
+
+	var statuses []domain.IssueStatus
+	for _, name := range strings.Split(value, ",") {
+		status := domain.IssueStatus(strings.TrimSpace(name))
+		if !slices.Contains(domain.IssueStatuses, status) {
+			return nil, fmt.Errorf("invalid status %q: must be all or a list of %s", name, joinStatuses(domain.IssueStatuses))
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func joinStatuses(statuses []domain.IssueStatus) string {
+	names := make([]string, len(statuses))
+	for i, status := range statuses {
+		names[i] = string(status)
+	}
+	return strings.Join(names, ", ")
+}
+
+// ListV1 returns a page of the open and regressed issues, or of those with
+// the requested statuses, as a plain array, sorted by count or by
+// recency. The next page is linked in the Link header and the total count,
+// when requested, is set in X-Total-Count.
+func (h *IssuesHandler) ListV1(c *gin.Context) {
+	query, err := parseIssueQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.service.ListIssues(c.Request.Context(), query)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if page.Next != nil {
+		c.Header("Link", fmt.Sprintf(`<%s>; rel="next"`, nextPageLink(c, page.Next.Encode())))
+	}
+	if page.Total != nil {
+		c.Header("X-Total-Count", strconv.FormatInt(*page.Total, 10))
+	}
+
+	c.JSON(http.StatusOK, page.Issues)
+}
+
+func (h *IssuesHandler) GetV1(c *gin.Context) {
+	issue, err := h.service.GetIssue(c.Request.Context(), c.Param("fingerprint"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, issue)
+}
+
+// ListReportsV1 returns a page of the reports of an issue, taking the same
+// query parameters as listing reports
+func (h *IssuesHandler) ListReportsV1(c *gin.Context) {
+	query, err := parseReportQuery(c, v1QueryNames)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.service.ListIssueReports(c.Request.Context(), c.Param("fingerprint"), query)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if page.Next != nil {
+		c.Header("Link", fmt.Sprintf(`<%s>; rel="next"`, nextReportsPageLink(c, page.Next, query.Filter)))
+	}
+	if page.Total != nil {
+		c.Header("X-Total-Count", strconv.FormatInt(*page.Total, 10))
+	}
+
+	c.JSON(http.StatusOK, page.Reports)
+}
+
+// UpdateStatusV1 resolves, ignores, snoozes or reopens an issue and returns
+// the updated issue
+func (h *IssuesHandler) UpdateStatusV1(c *gin.Context) {
+	var change domain.IssueStatusChange
+	if err := c.ShouldBindJSON(&change); err != nil {
+		c.JSON(bindStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	issue, err := h.service.UpdateIssueStatus(c.Request.Context(), c.Param("fingerprint"), change)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, issue)
+}
@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockIssuesService is a mock implementation of IssuesService
+type MockIssuesService struct {
+	mock.Mock
+}
+
+func (m *MockIssuesService) ListIssues(ctx context.Context, query application.IssueQuery) (*application.IssuePage, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*application.IssuePage), args.Error(1)
+}
+
+func (m *MockIssuesService) GetIssue(ctx context.Context, fingerprint string) (*domain.Issue, error) {
+	args := m.Called(ctx, fingerprint)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Issue), args.Error(1)
+}
+
+func (m *MockIssuesService) ListIssueReports(ctx context.Context, fingerprint string, query application.ReportQuery) (*application.ReportPage, error) {
+	args := m.Called(ctx, fingerprint, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*application.ReportPage), args.Error(1)
+}
+
+func (m *MockIssuesService) UpdateIssueStatus(ctx context.Context, fingerprint string, change domain.IssueStatusChange) (*domain.Issue, error) {
+	args := m.Called(ctx, fingerprint, change)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Issue), args.Error(1)
+}
+
+func setupIssueTestRouter(service *MockIssuesService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	v1 := router.Group("/v1")
+	setupIssueRoutesV1(v1, service)
+	return router
+}
+
+const testFingerprint = "0123456789abcdef0123456789abcdef"
+
+var testIssue = domain.Issue{
+	Fingerprint:        testFingerprint,
+	DocumentPath:       "example.com/checkout",
+	EffectiveDirective: "script-src-elem",
+	BlockedOrigin:      "https://cdn.example.net",
+	FirstSeen:          1700000000,
+	LastSeen:           1700086400,
+	Count:              42,
+	Status:             domain.IssueOpen,
+}
+
+const testIssueJSON = `{
+	"fingerprint": "` + testFingerprint + `",
+	"documentpath": "example.com/checkout",
+	"effectivedirective": "script-src-elem",
+	"blockedorigin": "https://cdn.example.net",
+	"sourcefile": "",
+	"firstseen": 1700000000,
+	"lastseen": 1700086400,
+	"count": 42,
+	"status": "open",
+	"statusnote": "",
+	"statusactor": "",
+	"statustime": 0,
+	"snoozeuntil": 0,
+	"regressedat": 0
+}`
+
+func TestIssuesV1(t *testing.T) {
+	total := int64(7)
+	after := &application.IssueCursor{Sort: application.IssueSortRecent, Count: 50, LastSeen: 1700000000, Fingerprint: testIssue.Fingerprint}
+	next := application.NewIssueCursor(application.IssueSortRecent, testIssue)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		setupMock      func(*MockIssuesService)
+		expectedStatus int
+		expectedBody   string
+		expectedLink   string
+	}{
+		{
+			name: "List",
+			path: "/v1/issues?sort=recent&limit=1&cursor=" + after.Encode() + "&total=true",
+			setupMock: func(m *MockIssuesService) {
+				m.On("ListIssues", mock.Anything, application.IssueQuery{
+					Sort: application.IssueSortRecent, Limit: 1, After: after, IncludeTotal: true,
+				}).Return(&application.IssuePage{Issues: []domain.Issue{testIssue}, Next: next, Total: &total}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[` + testIssueJSON + `]`,
+			expectedLink:   `</v1/issues?cursor=` + next.Encode() + `&limit=1&sort=recent&total=true>; rel="next"`,
+		},
+		{
+			name: "List Statuses",
+			path: "/v1/issues?status=resolved,%20snoozed",
+			setupMock: func(m *MockIssuesService) {
+				m.On("ListIssues", mock.Anything, application.IssueQuery{
+					Statuses: []domain.IssueStatus{domain.IssueResolved, domain.IssueSnoozed},
+				}).Return(&application.IssuePage{Issues: []domain.Issue{}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[]`,
+		},
+		{
+			name: "List All Statuses",
+			path: "/v1/issues?status=all",
+			setupMock: func(m *MockIssuesService) {
+				m.On("ListIssues", mock.Anything, application.IssueQuery{Statuses: domain.IssueStatuses}).
+					Return(&application.IssuePage{Issues: []domain.Issue{}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[]`,
+		},
+		{
+			name:           "List Invalid Status",
+			path:           "/v1/issues?status=open,closed",
+			setupMock:      func(m *MockIssuesService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error": "invalid status \"closed\": must be all or a list of open, resolved, ignored, snoozed, regressed"}`,
+		},
+		{
+			name:           "List Invalid Sort",
+			path:           "/v1/issues?sort=oldest",
+			setupMock:      func(m *MockIssuesService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error": "invalid sort \"oldest\": must be \"count\" or \"recent\""}`,
+		},
+		{
+			name:           "List Invalid Cursor",
+			path:           "/v1/issues?cursor=bm90LWEtY3Vyc29y",
+			setupMock:      func(m *MockIssuesService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error": "invalid cursor"}`,
+		},
+		{
+			name: "List Service Error",
+			path: "/v1/issues",
+			setupMock: func(m *MockIssuesService) {
+				m.On("ListIssues", mock.Anything, application.IssueQuery{}).Return(nil, errors.New("query failed"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"error": "internal server error"}`,
+		},
+		{
+			name: "Get",
+			path: "/v1/issues/" + testFingerprint,
+			setupMock: func(m *MockIssuesService) {
+				m.On("GetIssue", mock.Anything, testFingerprint).Return(&testIssue, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   testIssueJSON,
+		},
+		{
+			name: "Get Invalid Fingerprint",
+			path: "/v1/issues/nope",
+			setupMock: func(m *MockIssuesService) {
+				m.On("GetIssue", mock.Anything, "nope").Return(nil, application.ErrInvalidID)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error": "invalid ID"}`,
+		},
+		{
+			name: "Reports",
+			path: "/v1/issues/" + testFingerprint + "/reports?since=24h&limit=5",
+			setupMock: func(m *MockIssuesService) {
+				m.On("ListIssueReports", mock.Anything, testFingerprint, mock.MatchedBy(func(query application.ReportQuery) bool {
+					return query.Limit == 5 && query.Filter.From > 0
+				})).Return(&application.ReportPage{Reports: []domain.Report{}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[]`,
+		},
+		{
+			name: "Reports Unknown Issue",
+			path: "/v1/issues/" + testFingerprint + "/reports",
+			setupMock: func(m *MockIssuesService) {
+				m.On("ListIssueReports", mock.Anything, testFingerprint, application.ReportQuery{}).Return(nil, application.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error": "not found"}`,
+		},
+		{
+			name:   "Update Status",
+			method: "POST",
+			path:   "/v1/issues/" + testFingerprint + "/status",
+			body:   `{"status": "snoozed", "snoozecount": 100, "note": "flaky CDN", "actor": "alice", "time": 1}`,
+			setupMock: func(m *MockIssuesService) {
+				m.On("UpdateIssueStatus", mock.Anything, testFingerprint, domain.IssueStatusChange{
+					Status: domain.IssueSnoozed, SnoozeCount: 100, Note: "flaky CDN", Actor: "alice",
+				}).Return(&testIssue, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   testIssueJSON,
+		},
+		{
+			name:           "Update Status Invalid Body",
+			method:         "POST",
+			path:           "/v1/issues/" + testFingerprint + "/status",
+			body:           `{"status": `,
+			setupMock:      func(m *MockIssuesService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error": "unexpected EOF"}`,
+		},
+		{
+			name:   "Update Status Validation Error",
+			method: "POST",
+			path:   "/v1/issues/" + testFingerprint + "/status",
+			body:   `{"status": "resolved"}`,
+			setupMock: func(m *MockIssuesService) {
+				m.On("UpdateIssueStatus", mock.Anything, testFingerprint, domain.IssueStatusChange{Status: domain.IssueResolved}).
+					Return(nil, fmt.Errorf("%w: %w", application.ErrValidation, &domain.ValidationError{Errors: []domain.FieldError{
+						{Field: domain.FieldIssueActor, Message: "is required"},
+					}}))
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"error": "validation failed: actor: is required", "fields": [{"field": "actor", "message": "is required"}]}`,
+		},
+		{
+			name:   "Update Status Unknown Issue",
+			method: "POST",
+			path:   "/v1/issues/" + testFingerprint + "/status",
+			body:   `{"status": "ignored", "actor": "alice"}`,
+			setupMock: func(m *MockIssuesService) {
+				m.On("UpdateIssueStatus", mock.Anything, testFingerprint, domain.IssueStatusChange{Status: domain.IssueIgnored, Actor: "alice"}).
+					Return(nil, application.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error": "not found"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockIssuesService)
+			tt.setupMock(mockService)
+			router := setupIssueTestRouter(mockService)
+
+			method := tt.method
+			if method == "" {
+				method = "GET"
+			}
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(method, tt.path, strings.NewReader(tt.body))
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			assert.Equal(t, tt.expectedLink, w.Header().Get("Link"))
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+)
+
+// reportV2 is the V2 representation of a report, using the camelCase field
+// names of the browser Reporting API
+type reportV2 struct {
+	ID                 string    `json:"id"`
+	DocumentURL        string    `json:"documentURL"`
+	Referrer           string    `json:"referrer"`
+	ViolatedDirective  string    `json:"violatedDirective"`
+	EffectiveDirective string    `json:"effectiveDirective"`
+	OriginalPolicy     string    `json:"originalPolicy"`
+	Disposition        string    `json:"disposition"`
+	BlockedURL         string    `json:"blockedURL"`
+	LineNumber         int       `json:"lineNumber"`
+	SourceFile         string    `json:"sourceFile"`
+	StatusCode         int       `json:"statusCode"`
+	Sample             string    `json:"sample"`
+	ClientIP           string    `json:"clientIP"`
+	UserAgent          string    `json:"userAgent"`
+	ReportTime         time.Time `json:"reportTime"`
+	NoiseCategory      string    `json:"noiseCategory"`
+	NoiseReason        string    `json:"noiseReason"`
+	Fingerprint        string    `json:"fingerprint"`
+}
+
+// reportInputV2 is the V2 request body for creating a report. The ID, client
+// IP, user agent and report time are set by the server.
+type reportInputV2 struct {
+	DocumentURL        string `json:"documentURL"`
+	Referrer           string `json:"referrer"`
+	ViolatedDirective  string `json:"violatedDirective"`
+	EffectiveDirective string `json:"effectiveDirective"`
+	OriginalPolicy     string `json:"originalPolicy"`
+	Disposition        string `json:"disposition"`
+	BlockedURL         string `json:"blockedURL"`
+	LineNumber         int    `json:"lineNumber"`
+	SourceFile         string `json:"sourceFile"`
+	StatusCode         int    `json:"statusCode"`
+	Sample             string `json:"sample"`
+}
+
+func newReportV2(report domain.Report) reportV2 {
+	data := report.Report
+	return reportV2{
+		ID:                 report.ID.Hex(),
+		DocumentURL:        data.DocumentUri,
+		Referrer:           data.Referrer,
+		ViolatedDirective:  data.ViolatedDirective,
+		EffectiveDirective: data.EffectiveDirective,
+		OriginalPolicy:     data.OriginalPolicy,
+		Disposition:        data.Disposition,
+		BlockedURL:         data.BlockedUri,
+		LineNumber:         data.LineNumber,
+		SourceFile:         data.SourceFile,
+		StatusCode:         data.StatusCode,
+		Sample:             data.ScriptSample,
+		ClientIP:           data.ClientIP,
+		UserAgent:          data.UserAgent,
+		ReportTime:         time.Unix(int64(data.ReportTime), 0).UTC(),
+		NoiseCategory:      data.NoiseCategory,
+		NoiseReason:        data.NoiseReason,
+		Fingerprint:        data.Fingerprint,
+	}
+}
+
+func newReportsV2(reports []domain.Report) []reportV2 {
+	result := make([]reportV2, len(reports))
+	for i, report := range reports {
+		result[i] = newReportV2(report)
+	}
+	return result
+}
+
+func (in reportInputV2) toReportData() domain.ReportData {
+	return domain.ReportData{
+		DocumentUri:        in.DocumentURL,
+		Referrer:           in.Referrer,
+		ViolatedDirective:  in.ViolatedDirective,
+		EffectiveDirective: in.EffectiveDirective,
+		OriginalPolicy:     in.OriginalPolicy,
+		Disposition:        in.Disposition,
+		BlockedUri:         in.BlockedURL,
+		LineNumber:         in.LineNumber,
+		SourceFile:         in.SourceFile,
+		StatusCode:         in.StatusCode,
+		ScriptSample:       in.Sample,
+	}
+}
+
+// v2FieldNames maps report fields to their V2 names, used for query parameters
+// and field errors
+var v2FieldNames = fieldNames{
+	domain.FieldDocumentUri:        "documentURL",
+	domain.FieldViolatedDirective:  "violatedDirective",
+	domain.FieldEffectiveDirective: "effectiveDirective",
+	domain.FieldOriginalPolicy:     "originalPolicy",
+	domain.FieldBlockedUri:         "blockedURL",
+	domain.FieldLineNumber:         "lineNumber",
+	domain.FieldSourceFile:         "sourceFile",
+	domain.FieldStatusCode:         "statusCode",
+	domain.FieldScriptSample:       "sample",
+	domain.FieldUserAgent:          "userAgent",
+	domain.FieldDisposition:        "disposition",
+	domain.FieldNoiseCategory:      "noiseCategory",
+}
+
+// topEntryV2 is a value with its occurrence count in a V2 statistics response
+type topEntryV2 struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// topStatisticsV2 is the V2 representation of a top-N statistic
+type topStatisticsV2 struct {
+	Field   string       `json:"field"`
+	Entries []topEntryV2 `json:"entries"`
+}
+
+func newTopIPsV2(results []application.TopIPResult) topStatisticsV2 {
+	entries := make([]topEntryV2, len(results))
+	for i, result := range results {
+		entries[i] = topEntryV2{Value: result.IP, Count: result.Count}
+	}
+	return topStatisticsV2{Field: "clientIP", Entries: entries}
+}
+
+func newTopDirectivesV2(field string, results []application.TopDirectiveResult) topStatisticsV2 {
+	entries := make([]topEntryV2, len(results))
+	for i, result := range results {
+		entries[i] = topEntryV2{Value: result.Directive, Count: result.Count}
+	}
+	return topStatisticsV2{Field: field, Entries: entries}
+}
+
+func newTopBlockedURIsV2(results []application.TopBlockedURIResult) topStatisticsV2 {
+	entries := make([]topEntryV2, len(results))
+	for i, result := range results {
+		entries[i] = topEntryV2{Value: result.BlockedURI, Count: result.Count}
+	}
+	return topStatisticsV2{Field: "blockedURL", Entries: entries}
+}
+
+func newTopDocumentsV2(results []application.TopDocumentResult) topStatisticsV2 {
+	entries := make([]topEntryV2, len(results))
+	for i, result := range results {
+		entries[i] = topEntryV2{Value: result.DocumentURI, Count: result.Count}
+	}
+	return topStatisticsV2{Field: "documentURL", Entries: entries}
+}
+
+func newTopSourceFilesV2(results []application.TopSourceFileResult) topStatisticsV2 {
+	entries := make([]topEntryV2, len(results))
+	for i, result := range results {
+		entries[i] = topEntryV2{Value: result.SourceFile, Count: result.Count}
+	}
+	return topStatisticsV2{Field: "sourceFile", Entries: entries}
+}
+
+// timeSeriesV2 is the V2 representation of a time series
+type timeSeriesV2 struct {
+	Bucket  application.BucketSize `json:"bucket"`
+	From    time.Time              `json:"from"`
+	To      time.Time              `json:"to"`
+	GroupBy string                 `json:"groupBy,omitempty"`
+	Series  []timeSeriesGroupV2    `json:"series"`
+}
+
+type timeSeriesGroupV2 struct {
+	Group  string              `json:"group"`
+	Points []timeSeriesPointV2 `json:"points"`
+}
+
+type timeSeriesPointV2 struct {
+	Time  time.Time `json:"time"`
+	Count int       `json:"count"`
+}
+
+func newTimeSeriesV2(timeSeries *application.TimeSeries) timeSeriesV2 {
+	result := timeSeriesV2{
+		Bucket: timeSeries.Bucket,
+		From:   time.Unix(int64(timeSeries.From), 0).UTC(),
+		To:     time.Unix(int64(timeSeries.To), 0).UTC(),
+		Series: make([]timeSeriesGroupV2, len(timeSeries.Series)),
+	}
+	if timeSeries.GroupBy != "" {
+		result.GroupBy = v2FieldNames.name(timeSeries.GroupBy)
+	}
+
+	for i, group := range timeSeries.Series {
+		points := make([]timeSeriesPointV2, len(group.Points))
+		for j, point := range group.Points {
+			points[j] = timeSeriesPointV2{Time: time.Unix(int64(point.Time), 0).UTC(), Count: point.Count}
+		}
+		result.Series[i] = timeSeriesGroupV2{Group: group.Group, Points: points}
+	}
+
+	return result
+}
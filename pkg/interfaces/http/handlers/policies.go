@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/gin-gonic/gin"
+)
+
+type PoliciesHandler struct {
+	service application.PoliciesService
+}
+
+func NewPoliciesHandler(service application.PoliciesService) *PoliciesHandler {
+	return &PoliciesHandler{
+		service: service,
+	}
+}
+
+// V1 Routes
+func setupPolicyRoutesV1(router *gin.RouterGroup, service application.PoliciesService) {
+	handler := NewPoliciesHandler(service)
+	policies := router.Group("/policies")
+	{
+		policies.GET("/recommendation", handler.RecommendV1)
+	}
+}
+
+// RecommendV1 proposes a revised policy for the documents and time window
+// selected by the report filter, e.g. documenturi_prefix and since
+func (h *PoliciesHandler) RecommendV1(c *gin.Context) {
+	filter, err := parseReportFilter(c, v1QueryNames)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	params := application.PolicyRecommendationParams{Filter: filter}
+
+	if value := c.Query("min_count"); value != "" {
+		minCount, err := strconv.Atoi(value)
+		if err != nil || minCount < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid min_count %q: must be a positive integer", value)})
+			return
+		}
+		params.MinCount = minCount
+	}
+
+	recommendation, err := h.service.RecommendPolicy(c.Request.Context(), params)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, recommendation)
+}
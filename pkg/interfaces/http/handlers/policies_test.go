@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPoliciesService is a mock implementation of PoliciesService
+type MockPoliciesService struct {
+	mock.Mock
+}
+
+func (m *MockPoliciesService) RecommendPolicy(ctx context.Context, params application.PolicyRecommendationParams) (*application.PolicyRecommendation, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*application.PolicyRecommendation), args.Error(1)
+}
+
+func setupPolicyTestRouter(service *MockPoliciesService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	v1 := router.Group("/v1")
+	setupPolicyRoutesV1(v1, service)
+	return router
+}
+
+func TestRecommendPolicyV1(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(*MockPoliciesService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "Success",
+			query: "?documenturi_prefix=https://example.com/&from=1700000000&min_count=3",
+			setupMock: func(m *MockPoliciesService) {
+				m.On("RecommendPolicy", mock.Anything, application.PolicyRecommendationParams{
+					Filter: application.ReportFilter{
+						DocumentUri: &application.StringFilter{Value: "https://example.com/", Match: application.MatchPrefix},
+						From:        1700000000,
+					},
+					MinCount: 3,
+				}).Return(&application.PolicyRecommendation{
+					OriginalPolicy: "script-src 'self'",
+					Policy:         "script-src 'self' https://cdn.example.net",
+					Additions:      []application.SourceAddition{{Directive: "script-src", Source: "https://cdn.example.net", Count: 5}},
+					Excluded: []application.ExcludedSource{
+						{Directive: "script-src", Value: "chrome-extension://abcdef", Count: 4, Reason: application.ExcludedExtension},
+					},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"originalpolicy": "script-src 'self'",
+				"policy": "script-src 'self' https://cdn.example.net",
+				"additions": [{"directive": "script-src", "source": "https://cdn.example.net", "count": 5}],
+				"excluded": [{"directive": "script-src", "value": "chrome-extension://abcdef", "count": 4, "reason": "browser extension"}]
+			}`,
+		},
+		{
+			name:           "Invalid Min Count",
+			query:          "?min_count=0",
+			setupMock:      func(m *MockPoliciesService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error": "invalid min_count \"0\": must be a positive integer"}`,
+		},
+		{
+			name:  "No Matching Reports",
+			query: "?documenturi=https://unknown.example.com/",
+			setupMock: func(m *MockPoliciesService) {
+				m.On("RecommendPolicy", mock.Anything, mock.Anything).Return(nil, application.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error": "not found"}`,
+		},
+		{
+			name:  "Service Error",
+			query: "",
+			setupMock: func(m *MockPoliciesService) {
+				m.On("RecommendPolicy", mock.Anything, mock.Anything).Return(nil, errors.New("aggregation failed"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"error": "internal server error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockPoliciesService)
+			tt.setupMock(mockService)
+			router := setupPolicyTestRouter(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/v1/policies/recommendation"+tt.query, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
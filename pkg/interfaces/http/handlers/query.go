@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// fieldNames maps report fields to the names an API version uses for them.
+// Fields without an entry keep their domain name.
+type fieldNames map[string]string
+
+func (n fieldNames) name(field string) string {
+	if name, ok := n[field]; ok {
+		return name
+	}
+	return field
+}
+
+// queryNames defines the query parameter naming of an API version
+type queryNames struct {
+	fields         fieldNames
+	prefixSuffix   string
+	containsSuffix string
+	groupBy        string
+}
+
+var (
+	v1QueryNames = queryNames{prefixSuffix: "_prefix", containsSuffix: "_contains", groupBy: "groupby"}
+	v2QueryNames = queryNames{fields: v2FieldNames, prefixSuffix: "Prefix", containsSuffix: "Contains", groupBy: "groupBy"}
+)
+
+// parseReportQuery reads the report filter and the pagination parameters limit, sort, cursor and total
+func parseReportQuery(c *gin.Context, names queryNames) (application.ReportQuery, error) {
+	var query application.ReportQuery
+
+	filter, err := parseReportFilter(c, names)
+	if err != nil {
+		return query, err
+	}
+	query.Filter = filter
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			return query, fmt.Errorf("invalid limit %q: must be a positive integer", value)
+		}
+		query.Limit = limit
+	}
+
+	switch sort := application.SortOrder(c.Query("sort")); sort {
+	case "", application.SortNewest, application.SortOldest:
+		query.Sort = sort
+	default:
+		return query, fmt.Errorf("invalid sort %q: must be %q or %q", sort, application.SortNewest, application.SortOldest)
+	}
+
+	if value := c.Query("cursor"); value != "" {
+		cursor, err := application.DecodeCursor(value)
+		if err != nil {
+			return query, err
+		}
+		query.After = cursor
+	}
+
+	if value := c.Query("total"); value != "" {
+		total, err := strconv.ParseBool(value)
+		if err != nil {
+			return query, fmt.Errorf("invalid total %q: must be a boolean", value)
+		}
+		query.IncludeTotal = total
+	}
+
+	return query, nil
+}
+
+// parseStatisticsParams reads the report filter and the limit of a statistics
+// query. URI normalization is rejected, as only URI statistics support it.
+func parseStatisticsParams(c *gin.Context, names queryNames) (application.StatisticsParams, error) {
+	params, err := parseTopParams(c, names)
+	if err != nil {
+		return params, err
+	}
+
+	if normalize := c.Query("normalize"); normalize != "" {
+		return params, fmt.Errorf("invalid normalize %q: only supported for blocked URIs and source files", normalize)
+	}
+
+	return params, nil
+}
+
+// parseURIStatisticsParams reads the report filter, the limit and the URI
+// normalization of the statistics of blocked URIs and source files
+func parseURIStatisticsParams(c *gin.Context, names queryNames) (application.StatisticsParams, error) {
+	params, err := parseTopParams(c, names)
+	if err != nil {
+		return params, err
+	}
+
+	switch normalize := c.Query("normalize"); normalize {
+	case "":
+	case "origin":
+		params.NormalizeOrigin = true
+	default:
+		return params, fmt.Errorf("invalid normalize %q: must be \"origin\"", normalize)
+	}
+
+	return params, nil
+}
+
+// parseTopParams reads the report filter and the limit of a statistics query
+func parseTopParams(c *gin.Context, names queryNames) (application.StatisticsParams, error) {
+	var params application.StatisticsParams
+
+	filter, err := parseReportFilter(c, names)
+	if err != nil {
+		return params, err
+	}
+	params.Filter = filter
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > application.MaxStatisticsLimit {
+			return params, fmt.Errorf("invalid limit %q: must be between 1 and %d", value, application.MaxStatisticsLimit)
+		}
+		params.Limit = limit
+	}
+
+	return params, nil
+}
+
+// parseTimeSeriesParams reads the report filter, the bucket size and the group-by field of a time series
+func parseTimeSeriesParams(c *gin.Context, names queryNames) (application.TimeSeriesParams, error) {
+	var params application.TimeSeriesParams
+
+	filter, err := parseReportFilter(c, names)
+	if err != nil {
+		return params, err
+	}
+	params.Filter = filter
+
+	if value := c.Query("bucket"); value != "" {
+		bucket := application.BucketSize(value)
+		if bucket.Seconds() == 0 {
+			return params, fmt.Errorf("invalid bucket %q: must be %q, %q or %q", value,
+				application.BucketMinute, application.BucketHour, application.BucketDay)
+		}
+		params.Bucket = bucket
+	}
+
+	if value := c.Query(names.groupBy); value != "" {
+		allowed := make([]string, len(application.TimeSeriesGroupFields))
+		for i, field := range application.TimeSeriesGroupFields {
+			allowed[i] = names.fields.name(field)
+		}
+		index := slices.Index(allowed, value)
+		if index < 0 {
+			return params, fmt.Errorf("invalid %s %q: must be one of %s", names.groupBy, value, strings.Join(allowed, ", "))
+		}
+		params.GroupBy = application.TimeSeriesGroupFields[index]
+	}
+
+	return params, nil
+}
+
+// nextPageLink returns the URL of the current request with its cursor replaced
+func nextPageLink(c *gin.Context, cursor string) string {
+	values := c.Request.URL.Query()
+	values.Set("cursor", cursor)
+	return c.Request.URL.Path + "?" + values.Encode()
+}
+
+// nextReportsPageLink returns the link to the next page of a report listing
+// filtered by filter. A since parameter is replaced by the from it resolved
+// to, so the pages cover the same time range however late they are requested.
+func nextReportsPageLink(c *gin.Context, next *application.ReportCursor, filter application.ReportFilter) string {
+	values := c.Request.URL.Query()
+	if values.Has("since") {
+		values.Del("since")
+		values.Set("from", strconv.Itoa(filter.From))
+	}
+	values.Set("cursor", next.Encode())
+	return c.Request.URL.Path + "?" + values.Encode()
+}
+
+// parseReportFilter reads the report filter parameters. Every filterable field
+// is matched exactly by its own name and by prefix or substring with the
+// version's suffixes, e.g. documenturi_prefix=https://example.com/checkout in V1.
+// The report time range is given by from and to, or by since relative to now.
+func parseReportFilter(c *gin.Context, names queryNames) (application.ReportFilter, error) {
+	var filter application.ReportFilter
+
+	fields := []struct {
+		name   string
+		target **application.StringFilter
+	}{
+		{domain.FieldDocumentUri, &filter.DocumentUri},
+		{domain.FieldViolatedDirective, &filter.ViolatedDirective},
+		{domain.FieldEffectiveDirective, &filter.EffectiveDirective},
+		{domain.FieldBlockedUri, &filter.BlockedUri},
+		{domain.FieldSourceFile, &filter.SourceFile},
+		{domain.FieldDisposition, &filter.Disposition},
+		{domain.FieldNoiseCategory, &filter.NoiseCategory},
+		{domain.FieldFingerprint, &filter.Fingerprint},
+	}
+	matchSuffixes := []struct {
+		suffix string
+		match  application.MatchType
+	}{
+		{"", application.MatchExact},
+		{names.prefixSuffix, application.MatchPrefix},
+		{names.containsSuffix, application.MatchContains},
+	}
+
+	for _, field := range fields {
+		name := names.fields.name(field.name)
+		for _, matchSuffix := range matchSuffixes {
+			value, ok := c.GetQuery(name + matchSuffix.suffix)
+			if !ok {
+				continue
+			}
+			if *field.target != nil {
+				return filter, fmt.Errorf("only one filter may be given for %s", name)
+			}
+			*field.target = &application.StringFilter{Value: value, Match: matchSuffix.match}
+		}
+	}
+
+	var err error
+	if filter.From, err = parseTimeParam(c, "from"); err != nil {
+		return filter, err
+	}
+	if filter.To, err = parseTimeParam(c, "to"); err != nil {
+		return filter, err
+	}
+
+	if value := c.Query("since"); value != "" {
+		if filter.From != 0 {
+			return filter, fmt.Errorf("since and from cannot be combined")
+		}
+		since, err := time.ParseDuration(value)
+		if err != nil || since <= 0 {
+			return filter, fmt.Errorf("invalid since %q: must be a positive duration such as 24h", value)
+		}
+		filter.From = int(time.Now().Add(-since).Unix())
+	}
+
+	switch noise := application.NoiseFilter(c.Query("noise")); noise {
+	case "", application.NoiseInclude, application.NoiseExclude, application.NoiseOnly:
+		filter.Noise = noise
+	default:
+		return filter, fmt.Errorf("invalid noise %q: must be include, exclude or only", noise)
+	}
+
+	if filter.From != 0 && filter.To != 0 && filter.From >= filter.To {
+		return filter, fmt.Errorf("from must be before to")
+	}
+
+	return filter, nil
+}
+
+// parseTimeParam reads a time given as Unix seconds or RFC 3339, returning Unix seconds
+func parseTimeParam(c *gin.Context, name string) (int, error) {
+	value := c.Query(name)
+	if value == "" {
+		return 0, nil
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil && seconds > 0 {
+		return int(seconds), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return int(t.Unix()), nil
+	}
+
+	return 0, fmt.Errorf("invalid %s %q: must be Unix seconds or an RFC 3339 time", name, value)
+}
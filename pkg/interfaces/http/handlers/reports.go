@@ -1,42 +1,54 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
+	"path"
+	"strconv"
+	"time"
 
 	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
 	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain/csp"
 	"github.com/gin-gonic/gin"
 )
 
 type ReportsHandler struct {
 	service application.ReportsService
+	limits  Limits
 }
 
-func NewReportsHandler(service application.ReportsService) *ReportsHandler {
+func NewReportsHandler(service application.ReportsService, limits Limits) *ReportsHandler {
 	return &ReportsHandler{
 		service: service,
+		limits:  limits,
 	}
 }
 
 // V1 Routes
-func setupReportRoutesV1(router *gin.RouterGroup, service application.ReportsService) {
-	handler := NewReportsHandler(service)
+func setupReportRoutesV1(router *gin.RouterGroup, service application.ReportsService, limits Limits) {
+	handler := NewReportsHandler(service, limits)
 	reports := router.Group("/reports")
 	{
-		reports.POST("", handler.CreateV1)
+		ingest := reports.Group("", limits.Ingest...)
+		ingest.POST("", handler.CreateV1)
+		ingest.POST("/csp-report", handler.IngestCSPReportV1)
+		ingest.POST("/reporting-api", handler.IngestReportingAPIV1)
 		reports.GET("", handler.ListV1)
 		reports.GET("/:id", handler.GetV1)
+		reports.GET("/:id/policy", handler.GetPolicyV1)
 	}
 }
 
-// V2 Routes (for future implementation)
-func setupReportRoutesV2(router *gin.RouterGroup, service application.ReportsService) {
-	handler := NewReportsHandler(service)
+// V2 Routes
+func setupReportRoutesV2(router *gin.RouterGroup, service application.ReportsService, limits Limits) {
+	handler := NewReportsHandler(service, limits)
 	reports := router.Group("/reports")
 	{
-		reports.POST("", handler.CreateV2)
+		reports.Group("", limits.Ingest...).POST("", handler.CreateV2)
 		reports.GET("", handler.ListV2)
 		reports.GET("/:id", handler.GetV2)
+		reports.GET("/:id/policy", handler.GetPolicyV2)
 	}
 }
 
@@ -44,51 +56,187 @@ func setupReportRoutesV2(router *gin.RouterGroup, service application.ReportsSer
 func (h *ReportsHandler) CreateV1(c *gin.Context) {
 	var report domain.Report
 	if err := c.ShouldBindJSON(&report); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(bindStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
+	enrichReport(c, &report.Report, time.Now())
+
 	if err := h.service.CreateReport(c.Request.Context(), &report); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
+	c.Header("Location", path.Join(c.FullPath(), report.ID.Hex()))
 	c.JSON(http.StatusCreated, report)
 }
 
+// IngestCSPReportV1 accepts the legacy report format browsers send to a report-uri directive
+func (h *ReportsHandler) IngestCSPReportV1(c *gin.Context) {
+	var payload domain.CSPReport
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(bindStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	report := domain.Report{Report: payload.Body.ToReportData()}
+	enrichReport(c, &report.Report, time.Now())
+	if err := h.service.CreateReport(c.Request.Context(), &report); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// IngestReportingAPIV1 accepts a W3C Reporting API batch and stores its csp-violation entries
+func (h *ReportsHandler) IngestReportingAPIV1(c *gin.Context) {
+	var payload []domain.ReportingAPIReport
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(bindStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if h.limits.MaxBatchReports > 0 && len(payload) > h.limits.MaxBatchReports {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error": fmt.Sprintf("batch of %d reports exceeds the limit of %d", len(payload), h.limits.MaxBatchReports),
+		})
+		return
+	}
+
+	receivedAt := time.Now()
+	reports := make([]domain.Report, 0, len(payload))
+	for _, entry := range payload {
+		if !entry.IsCSPViolation() {
+			continue
+		}
+		data := entry.ToReportData(receivedAt)
+		enrichReport(c, &data, time.Unix(int64(data.ReportTime), 0))
+		reports = append(reports, domain.Report{Report: data})
+	}
+
+	if err := h.service.CreateReports(c.Request.Context(), reports); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
 func (h *ReportsHandler) GetV1(c *gin.Context) {
 	id := c.Param("id")
 	report, err := h.service.GetReport(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, report)
 }
 
+// GetPolicyV1 returns the parsed original policy of a report
+func (h *ReportsHandler) GetPolicyV1(c *gin.Context) {
+	report, err := h.service.GetReport(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, csp.Parse(report.Report.OriginalPolicy))
+}
+
+// ListV1 returns a page of reports as a plain array. The next page is linked in
+// the Link header and the total count, when requested, is set in X-Total-Count.
 func (h *ReportsHandler) ListV1(c *gin.Context) {
-	reports, err := h.service.ListReports(c.Request.Context())
+	query, err := parseReportQuery(c, v1QueryNames)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.service.ListReports(c.Request.Context(), query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, reports)
+	if page.Next != nil {
+		c.Header("Link", fmt.Sprintf(`<%s>; rel="next"`, nextReportsPageLink(c, page.Next, query.Filter)))
+	}
+	if page.Total != nil {
+		c.Header("X-Total-Count", strconv.FormatInt(*page.Total, 10))
+	}
+
+	c.JSON(http.StatusOK, page.Reports)
+}
+
+// enrichReport overwrites the fields a client must not be able to forge with
+// values observed by the server. The client IP honours the router's trusted proxies.
+func enrichReport(c *gin.Context, data *domain.ReportData, reportTime time.Time) {
+	data.ClientIP = c.ClientIP()
+	data.UserAgent = c.Request.UserAgent()
+	data.ReportTime = int(reportTime.Unix())
 }
 
-// V2 Handlers (for future implementation)
+// V2 Handlers
 func (h *ReportsHandler) CreateV2(c *gin.Context) {
-	// Implement V2 create logic when needed
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "V2 not implemented yet"})
+	var input reportInputV2
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondProblem(c, bindStatus(err), err.Error())
+		return
+	}
+
+	report := domain.Report{Report: input.toReportData()}
+	enrichReport(c, &report.Report, time.Now())
+
+	if err := h.service.CreateReport(c.Request.Context(), &report); err != nil {
+		respondErrorProblem(c, err)
+		return
+	}
+
+	c.Header("Location", path.Join(c.FullPath(), report.ID.Hex()))
+	respondV2(c, http.StatusCreated, newReportV2(report), nil)
 }
 
 func (h *ReportsHandler) GetV2(c *gin.Context) {
-	// Implement V2 get logic when needed
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "V2 not implemented yet"})
+	report, err := h.service.GetReport(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondErrorProblem(c, err)
+		return
+	}
+
+	respondV2(c, http.StatusOK, newReportV2(*report), nil)
+}
+
+// GetPolicyV2 returns the parsed original policy of a report
+func (h *ReportsHandler) GetPolicyV2(c *gin.Context) {
+	report, err := h.service.GetReport(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondErrorProblem(c, err)
+		return
+	}
+
+	respondV2(c, http.StatusOK, csp.Parse(report.Report.OriginalPolicy), nil)
 }
 
 func (h *ReportsHandler) ListV2(c *gin.Context) {
-	// Implement V2 list logic when needed
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "V2 not implemented yet"})
+	query, err := parseReportQuery(c, v2QueryNames)
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	query.Normalize()
+
+	page, err := h.service.ListReports(c.Request.Context(), query)
+	if err != nil {
+		respondErrorProblem(c, err)
+		return
+	}
+
+	meta := pageMeta{Limit: query.Limit, Total: page.Total}
+	if page.Next != nil {
+		meta.NextCursor = page.Next.Encode()
+		meta.Next = nextReportsPageLink(c, page.Next, query.Filter)
+	}
+
+	respondV2(c, http.StatusOK, newReportsV2(page.Reports), meta)
 }
@@ -5,14 +5,18 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
 	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -26,6 +30,11 @@ func (m *MockReportsService) CreateReport(ctx context.Context, report *domain.Re
 	return args.Error(0)
 }
 
+func (m *MockReportsService) CreateReports(ctx context.Context, reports []domain.Report) error {
+	args := m.Called(ctx, reports)
+	return args.Error(0)
+}
+
 func (m *MockReportsService) GetReport(ctx context.Context, id string) (*domain.Report, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -34,26 +43,27 @@ func (m *MockReportsService) GetReport(ctx context.Context, id string) (*domain.
 	return args.Get(0).(*domain.Report), args.Error(1)
 }
 
-func (m *MockReportsService) ListReports(ctx context.Context) ([]domain.Report, error) {
-	args := m.Called(ctx)
+func (m *MockReportsService) ListReports(ctx context.Context, query application.ReportQuery) (*application.ReportPage, error) {
+	args := m.Called(ctx, query)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]domain.Report), args.Error(1)
+	return args.Get(0).(*application.ReportPage), args.Error(1)
 }
 
 func setupReportTestRouter(service *MockReportsService) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
 	v1 := router.Group("/v1")
-	setupReportRoutesV1(v1, service)
+	setupReportRoutesV1(v1, service, Limits{MaxBatchReports: 3})
 	v2 := router.Group("/v2")
-	setupReportRoutesV2(v2, service)
+	setupReportRoutesV2(v2, service, Limits{})
 	return router
 }
 
 func TestCreateReportV1(t *testing.T) {
 	testID := primitive.NewObjectID()
+	createdID := primitive.NewObjectID()
 
 	tests := []struct {
 		name           string
@@ -65,7 +75,11 @@ func TestCreateReportV1(t *testing.T) {
 		{
 			name: "Success",
 			setupMock: func(m *MockReportsService) {
-				m.On("CreateReport", mock.Anything, mock.AnythingOfType("*domain.Report")).Return(nil)
+				m.On("CreateReport", mock.Anything, mock.AnythingOfType("*domain.Report")).
+					Run(func(args mock.Arguments) {
+						args.Get(1).(*domain.Report).ID = createdID
+					}).
+					Return(nil)
 			},
 			requestBody: domain.Report{
 				ID: testID,
@@ -77,11 +91,11 @@ func TestCreateReportV1(t *testing.T) {
 			},
 			expectedStatus: http.StatusCreated,
 			expectedBody: domain.Report{
-				ID: testID,
+				ID: createdID,
 				Report: domain.ReportData{
 					DocumentUri:       "https://example.com",
 					ViolatedDirective: "script-src",
-					ClientIP:          "192.168.1.1",
+					ClientIP:          "203.0.113.7",
 				},
 			},
 		},
@@ -97,7 +111,40 @@ func TestCreateReportV1(t *testing.T) {
 				},
 			},
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   gin.H{"error": "service error"},
+			expectedBody:   gin.H{"error": "internal server error"},
+		},
+		{
+			name: "Validation Error",
+			setupMock: func(m *MockReportsService) {
+				err := fmt.Errorf("%w: document is rejected by the collection validator", application.ErrValidation)
+				m.On("CreateReport", mock.Anything, mock.AnythingOfType("*domain.Report")).Return(err)
+			},
+			requestBody: domain.Report{
+				Report: domain.ReportData{
+					DocumentUri: "https://example.com",
+				},
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   gin.H{"error": "validation failed: document is rejected by the collection validator"},
+		},
+		{
+			name: "Invalid Report",
+			setupMock: func(m *MockReportsService) {
+				err := fmt.Errorf("%w: %w", application.ErrValidation, &domain.ValidationError{Errors: []domain.FieldError{
+					{Field: domain.FieldDocumentUri, Message: "must be an absolute URL"},
+				}})
+				m.On("CreateReport", mock.Anything, mock.AnythingOfType("*domain.Report")).Return(err)
+			},
+			requestBody: domain.Report{
+				Report: domain.ReportData{
+					DocumentUri: "example.com",
+				},
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody: gin.H{
+				"error":  "validation failed: documenturi: must be an absolute URL",
+				"fields": []gin.H{{"field": "documenturi", "message": "must be an absolute URL"}},
+			},
 		},
 		{
 			name:           "Invalid Request Body",
@@ -118,6 +165,7 @@ func TestCreateReportV1(t *testing.T) {
 			w := httptest.NewRecorder()
 			req, _ := http.NewRequest("POST", "/v1/reports", bytes.NewBuffer(body))
 			req.Header.Set("Content-Type", "application/json")
+			req.RemoteAddr = "203.0.113.7:51234"
 			router.ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
@@ -131,6 +179,8 @@ func TestCreateReportV1(t *testing.T) {
 				var actualReport domain.Report
 				err = json.Unmarshal(w.Body.Bytes(), &actualReport)
 				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedBody.(domain.Report).ID, actualReport.ID)
+				assert.Equal(t, "/v1/reports/"+actualReport.ID.Hex(), w.Header().Get("Location"))
 				assert.Equal(t, tt.expectedBody.(domain.Report).Report.DocumentUri, actualReport.Report.DocumentUri)
 				assert.Equal(t, tt.expectedBody.(domain.Report).Report.ViolatedDirective, actualReport.Report.ViolatedDirective)
 				assert.Equal(t, tt.expectedBody.(domain.Report).Report.ClientIP, actualReport.Report.ClientIP)
@@ -145,6 +195,219 @@ func TestCreateReportV1(t *testing.T) {
 	}
 }
 
+func TestCreateReportV1Enrichment(t *testing.T) {
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		forwardedFor   string
+		expectedIP     string
+	}{
+		{
+			name:         "Untrusted Forwarded Header",
+			forwardedFor: "198.51.100.20",
+			expectedIP:   "10.0.0.5",
+		},
+		{
+			name:           "Trusted Proxy",
+			trustedProxies: []string{"10.0.0.0/8"},
+			forwardedFor:   "198.51.100.20",
+			expectedIP:     "198.51.100.20",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockReportsService)
+			mockService.On("CreateReport", mock.Anything, mock.AnythingOfType("*domain.Report")).Return(nil)
+			router := setupReportTestRouter(mockService)
+			assert.NoError(t, router.SetTrustedProxies(tt.trustedProxies))
+
+			body := `{"report": {"documenturi": "https://example.com", "clientip": "1.2.3.4", "useragent": "forged", "reporttime": 1}}`
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/v1/reports", bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("User-Agent", "Mozilla/5.0")
+			req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			req.RemoteAddr = "10.0.0.5:51234"
+			before := time.Now().Unix()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusCreated, w.Code)
+
+			var actualReport domain.Report
+			err := json.Unmarshal(w.Body.Bytes(), &actualReport)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedIP, actualReport.Report.ClientIP)
+			assert.Equal(t, "Mozilla/5.0", actualReport.Report.UserAgent)
+			assert.GreaterOrEqual(t, int64(actualReport.Report.ReportTime), before)
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIngestCSPReportV1(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupMock      func(*MockReportsService)
+		requestBody    string
+		expectedStatus int
+	}{
+		{
+			name: "Success",
+			setupMock: func(m *MockReportsService) {
+				m.On("CreateReport", mock.Anything, mock.MatchedBy(func(r *domain.Report) bool {
+					return r.Report.DocumentUri == "https://example.com/checkout" &&
+						r.Report.ViolatedDirective == "script-src 'self'" &&
+						r.Report.EffectiveDirective == "script-src" &&
+						r.Report.BlockedUri == "https://cdn.example.net/app.js" &&
+						r.Report.LineNumber == 12 &&
+						r.Report.Disposition == "enforce"
+				})).Return(nil)
+			},
+			requestBody: `{"csp-report": {
+				"document-uri": "https://example.com/checkout",
+				"violated-directive": "script-src 'self'",
+				"original-policy": "script-src 'self'; report-uri /api/v1/reports/csp-report",
+				"blocked-uri": "https://cdn.example.net/app.js",
+				"line-number": 12
+			}}`,
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "Service Error",
+			setupMock: func(m *MockReportsService) {
+				m.On("CreateReport", mock.Anything, mock.AnythingOfType("*domain.Report")).Return(errors.New("service error"))
+			},
+			requestBody:    `{"csp-report": {"document-uri": "https://example.com"}}`,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "Invalid Request Body",
+			setupMock:      func(m *MockReportsService) {},
+			requestBody:    `not json`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockReportsService)
+			tt.setupMock(mockService)
+			router := setupReportTestRouter(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/v1/reports/csp-report", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/csp-report")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIngestReportingAPIV1(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupMock      func(*MockReportsService)
+		requestBody    string
+		expectedStatus int
+	}{
+		{
+			name: "Success",
+			setupMock: func(m *MockReportsService) {
+				m.On("CreateReports", mock.Anything, mock.MatchedBy(func(reports []domain.Report) bool {
+					if len(reports) != 2 {
+						return false
+					}
+					first := reports[0].Report
+					age := time.Now().Unix() - int64(first.ReportTime)
+					return first.DocumentUri == "https://example.com/checkout" &&
+						first.EffectiveDirective == "script-src-elem" &&
+						first.BlockedUri == "https://cdn.example.net/app.js" &&
+						first.ScriptSample == "alert(1)" &&
+						age >= 60 && age <= 62 &&
+						reports[1].Report.DocumentUri == "https://example.com/fallback"
+				})).Return(nil)
+			},
+			requestBody: `[
+				{
+					"type": "csp-violation",
+					"age": 60000,
+					"url": "https://example.com/checkout",
+					"user_agent": "Mozilla/5.0",
+					"body": {
+						"documentURL": "https://example.com/checkout",
+						"blockedURL": "https://cdn.example.net/app.js",
+						"effectiveDirective": "script-src-elem",
+						"originalPolicy": "script-src 'self'",
+						"sample": "alert(1)",
+						"disposition": "enforce",
+						"statusCode": 200,
+						"lineNumber": 3
+					}
+				},
+				{"type": "deprecation", "age": 10, "url": "https://example.com/", "body": {}},
+				{"type": "csp-violation", "age": 0, "url": "https://example.com/fallback", "body": {"effectiveDirective": "img-src"}}
+			]`,
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "Service Error",
+			setupMock: func(m *MockReportsService) {
+				m.On("CreateReports", mock.Anything, mock.Anything).Return(errors.New("service error"))
+			},
+			requestBody:    `[{"type": "csp-violation", "url": "https://example.com", "body": {}}]`,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "Invalid Request Body",
+			setupMock:      func(m *MockReportsService) {},
+			requestBody:    `{"type": "csp-violation"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Batch Too Large",
+			setupMock:      func(m *MockReportsService) {},
+			requestBody:    `[{"type": "csp-violation"}, {"type": "csp-violation"}, {"type": "csp-violation"}, {"type": "csp-violation"}]`,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockReportsService)
+			tt.setupMock(mockService)
+			router := setupReportTestRouter(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/v1/reports/reporting-api", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/reports+json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIngestBodyTooLarge(t *testing.T) {
+	mockService := new(MockReportsService)
+	router := setupReportTestRouter(mockService)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/v1/reports/csp-report", bytes.NewBufferString(`{"csp-report": {"document-uri": "https://example.com"}}`))
+	req.Body = http.MaxBytesReader(w, req.Body, 16)
+	req.Header.Set("Content-Type", "application/csp-report")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	mockService.AssertExpectations(t)
+}
+
 func TestGetReportV1(t *testing.T) {
 	testID := primitive.NewObjectID()
 
@@ -181,11 +444,30 @@ func TestGetReportV1(t *testing.T) {
 		{
 			name: "Not Found",
 			setupMock: func(m *MockReportsService) {
-				m.On("GetReport", mock.Anything, "non-existent").Return(nil, errors.New("report not found"))
+				m.On("GetReport", mock.Anything, "non-existent").Return(nil, application.ErrNotFound)
 			},
 			reportID:       "non-existent",
 			expectedStatus: http.StatusNotFound,
-			expectedBody:   gin.H{"error": "report not found"},
+			expectedBody:   gin.H{"error": "not found"},
+		},
+		{
+			name: "Invalid ID",
+			setupMock: func(m *MockReportsService) {
+				m.On("GetReport", mock.Anything, "not-an-id").Return(nil, application.ErrInvalidID)
+			},
+			reportID:       "not-an-id",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   gin.H{"error": "invalid ID"},
+		},
+		{
+			name: "Storage Unavailable",
+			setupMock: func(m *MockReportsService) {
+				err := fmt.Errorf("%w: %w", application.ErrUnavailable, errors.New("server selection error: context deadline exceeded"))
+				m.On("GetReport", mock.Anything, testID.Hex()).Return(nil, err)
+			},
+			reportID:       testID.Hex(),
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   gin.H{"error": "storage unavailable"},
 		},
 	}
 
@@ -223,23 +505,30 @@ func TestGetReportV1(t *testing.T) {
 
 func TestListReportsV1(t *testing.T) {
 	testID := primitive.NewObjectID()
+	total := int64(42)
+	next := &application.ReportCursor{ReportTime: 1700000000, ID: testID.Hex()}
 
 	tests := []struct {
 		name           string
 		setupMock      func(*MockReportsService)
+		query          string
 		expectedStatus int
 		expectedBody   interface{}
+		expectedLink   string
+		expectedTotal  string
 	}{
 		{
 			name: "Success",
 			setupMock: func(m *MockReportsService) {
-				m.On("ListReports", mock.Anything).Return([]domain.Report{
-					{
-						ID: testID,
-						Report: domain.ReportData{
-							DocumentUri:       "https://example.com",
-							ViolatedDirective: "script-src",
-							ClientIP:          "192.168.1.1",
+				m.On("ListReports", mock.Anything, application.ReportQuery{}).Return(&application.ReportPage{
+					Reports: []domain.Report{
+						{
+							ID: testID,
+							Report: domain.ReportData{
+								DocumentUri:       "https://example.com",
+								ViolatedDirective: "script-src",
+								ClientIP:          "192.168.1.1",
+							},
 						},
 					},
 				}, nil)
@@ -256,13 +545,129 @@ func TestListReportsV1(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Next Page And Total",
+			setupMock: func(m *MockReportsService) {
+				m.On("ListReports", mock.Anything, application.ReportQuery{
+					Limit:        1,
+					Sort:         application.SortOldest,
+					IncludeTotal: true,
+				}).Return(&application.ReportPage{
+					Reports: []domain.Report{{ID: testID}},
+					Next:    next,
+					Total:   &total,
+				}, nil)
+			},
+			query:          "?limit=1&sort=asc&total=true",
+			expectedStatus: http.StatusOK,
+			expectedBody:   []domain.Report{{ID: testID}},
+			expectedLink:   `</v1/reports?cursor=` + next.Encode() + `&limit=1&sort=asc&total=true>; rel="next"`,
+			expectedTotal:  "42",
+		},
+		{
+			name: "Cursor",
+			setupMock: func(m *MockReportsService) {
+				m.On("ListReports", mock.Anything, application.ReportQuery{After: next}).
+					Return(&application.ReportPage{Reports: []domain.Report{}}, nil)
+			},
+			query:          "?cursor=" + next.Encode(),
+			expectedStatus: http.StatusOK,
+			expectedBody:   []domain.Report{},
+		},
+		{
+			name: "Filters",
+			setupMock: func(m *MockReportsService) {
+				m.On("ListReports", mock.Anything, application.ReportQuery{
+					Filter: application.ReportFilter{
+						DocumentUri:        &application.StringFilter{Value: "/checkout", Match: application.MatchContains},
+						EffectiveDirective: &application.StringFilter{Value: "script-src", Match: application.MatchExact},
+						BlockedUri:         &application.StringFilter{Value: "https://cdn.example.net", Match: application.MatchPrefix},
+						From:               1700000000,
+						To:                 1700086400,
+					},
+				}).Return(&application.ReportPage{Reports: []domain.Report{}}, nil)
+			},
+			query:          "?effectivedirective=script-src&documenturi_contains=/checkout&blockeduri_prefix=https://cdn.example.net&from=1700000000&to=2023-11-15T22:13:20Z",
+			expectedStatus: http.StatusOK,
+			expectedBody:   []domain.Report{},
+		},
+		{
+			name:           "Conflicting Filters",
+			setupMock:      func(m *MockReportsService) {},
+			query:          "?documenturi=https://example.com&documenturi_prefix=https://example.com",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   gin.H{"error": "only one filter may be given for documenturi"},
+		},
+		{
+			name: "Noise Filters",
+			setupMock: func(m *MockReportsService) {
+				m.On("ListReports", mock.Anything, application.ReportQuery{
+					Filter: application.ReportFilter{
+						NoiseCategory: &application.StringFilter{Value: domain.NoiseExtension, Match: application.MatchExact},
+						Noise:         application.NoiseOnly,
+					},
+				}).Return(&application.ReportPage{Reports: []domain.Report{}}, nil)
+			},
+			query:          "?noise=only&noisecategory=extension",
+			expectedStatus: http.StatusOK,
+			expectedBody:   []domain.Report{},
+		},
+		{
+			name: "Noise Excluded With Noise Category",
+			setupMock: func(m *MockReportsService) {
+				m.On("ListReports", mock.Anything, application.ReportQuery{
+					Filter: application.ReportFilter{
+						NoiseCategory: &application.StringFilter{Value: "ext", Match: application.MatchPrefix},
+						Noise:         application.NoiseExclude,
+					},
+				}).Return(&application.ReportPage{Reports: []domain.Report{}}, nil)
+			},
+			query:          "?noise=exclude&noisecategory_prefix=ext",
+			expectedStatus: http.StatusOK,
+			expectedBody:   []domain.Report{},
+		},
+		{
+			name:           "Invalid Noise Filter",
+			setupMock:      func(m *MockReportsService) {},
+			query:          "?noise=some",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   gin.H{"error": `invalid noise "some": must be include, exclude or only`},
+		},
+		{
+			name:           "Invalid Time Range",
+			setupMock:      func(m *MockReportsService) {},
+			query:          "?from=1700086400&to=1700000000",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   gin.H{"error": "from must be before to"},
+		},
+		{
+			name:           "Invalid Limit",
+			setupMock:      func(m *MockReportsService) {},
+			query:          "?limit=-5",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   gin.H{"error": `invalid limit "-5": must be a positive integer`},
+		},
+		{
+			name:           "Invalid Sort",
+			setupMock:      func(m *MockReportsService) {},
+			query:          "?sort=random",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   gin.H{"error": `invalid sort "random": must be "desc" or "asc"`},
+		},
+		{
+			name:           "Invalid Cursor",
+			setupMock:      func(m *MockReportsService) {},
+			query:          "?cursor=not-a-cursor",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   gin.H{"error": "invalid cursor"},
+		},
 		{
 			name: "Service Error",
 			setupMock: func(m *MockReportsService) {
-				m.On("ListReports", mock.Anything).Return(nil, errors.New("service error"))
+				m.On("ListReports", mock.Anything, mock.Anything).Return(nil, errors.New("service error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   gin.H{"error": "service error"},
+			expectedBody:   gin.H{"error": "internal server error"},
 		},
 	}
 
@@ -273,7 +678,7 @@ func TestListReportsV1(t *testing.T) {
 			router := setupReportTestRouter(mockService)
 
 			w := httptest.NewRecorder()
-			req, _ := http.NewRequest("GET", "/v1/reports", nil)
+			req, _ := http.NewRequest("GET", "/v1/reports"+tt.query, nil)
 			router.ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
@@ -289,6 +694,8 @@ func TestListReportsV1(t *testing.T) {
 					assert.Equal(t, expectedReport.Report.ViolatedDirective, actualReports[i].Report.ViolatedDirective)
 					assert.Equal(t, expectedReport.Report.ClientIP, actualReports[i].Report.ClientIP)
 				}
+				assert.Equal(t, tt.expectedLink, w.Header().Get("Link"))
+				assert.Equal(t, tt.expectedTotal, w.Header().Get("X-Total-Count"))
 			} else {
 				var response map[string]string
 				err := json.Unmarshal(w.Body.Bytes(), &response)
@@ -301,44 +708,480 @@ func TestListReportsV1(t *testing.T) {
 	}
 }
 
-func TestReportsV2Endpoints(t *testing.T) {
+func TestListReportsSinceNextPage(t *testing.T) {
+	next := &application.ReportCursor{ReportTime: 1700000000, ID: primitive.NewObjectID().Hex()}
+
+	var from int
 	mockService := new(MockReportsService)
+	mockService.On("ListReports", mock.Anything, mock.MatchedBy(func(query application.ReportQuery) bool {
+		from = query.Filter.From
+		return from > 0
+	})).Return(&application.ReportPage{Reports: []domain.Report{}, Next: next}, nil)
 	router := setupReportTestRouter(mockService)
 
+	// The next page keeps the time range the first one resolved since to
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/reports?since=24h&limit=1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, fmt.Sprintf(`</v1/reports?cursor=%s&from=%d&limit=1>; rel="next"`, next.Encode(), from), w.Header().Get("Link"))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v2/reports?since=24h&limit=1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Meta struct {
+			Next string `json:"next"`
+		} `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, fmt.Sprintf("/v2/reports?cursor=%s&from=%d&limit=1", next.Encode(), from), response.Meta.Next)
+}
+
+func TestCreateReportV2(t *testing.T) {
+	createdID := primitive.NewObjectID()
+
 	tests := []struct {
-		name     string
-		method   string
-		endpoint string
+		name           string
+		setupMock      func(*MockReportsService)
+		requestBody    string
+		expectedStatus int
 	}{
 		{
-			name:     "CreateV2",
-			method:   "POST",
-			endpoint: "/v2/reports",
+			name: "Success",
+			setupMock: func(m *MockReportsService) {
+				m.On("CreateReport", mock.Anything, mock.MatchedBy(func(r *domain.Report) bool {
+					return r.Report.DocumentUri == "https://example.com/checkout" &&
+						r.Report.BlockedUri == "https://cdn.example.net/app.js" &&
+						r.Report.ScriptSample == "alert(1)"
+				})).
+					Run(func(args mock.Arguments) {
+						args.Get(1).(*domain.Report).ID = createdID
+					}).
+					Return(nil)
+			},
+			requestBody:    `{"documentURL": "https://example.com/checkout", "blockedURL": "https://cdn.example.net/app.js", "effectiveDirective": "script-src", "sample": "alert(1)"}`,
+			expectedStatus: http.StatusCreated,
 		},
 		{
-			name:     "GetV2",
-			method:   "GET",
-			endpoint: "/v2/reports/test-id",
+			name: "Service Error",
+			setupMock: func(m *MockReportsService) {
+				m.On("CreateReport", mock.Anything, mock.AnythingOfType("*domain.Report")).Return(errors.New("connection reset by peer"))
+			},
+			requestBody:    `{"documentURL": "https://example.com"}`,
+			expectedStatus: http.StatusInternalServerError,
 		},
 		{
-			name:     "ListV2",
-			method:   "GET",
-			endpoint: "/v2/reports",
+			name:           "Invalid Request Body",
+			setupMock:      func(m *MockReportsService) {},
+			requestBody:    `"invalid json"`,
+			expectedStatus: http.StatusBadRequest,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockReportsService)
+			tt.setupMock(mockService)
+			router := setupReportTestRouter(mockService)
+
 			w := httptest.NewRecorder()
-			req, _ := http.NewRequest(tt.method, tt.endpoint, nil)
+			req, _ := http.NewRequest("POST", "/v2/reports", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			req.RemoteAddr = "203.0.113.7:51234"
 			router.ServeHTTP(w, req)
 
-			assert.Equal(t, http.StatusNotImplemented, w.Code)
+			assert.Equal(t, tt.expectedStatus, w.Code)
 
-			var response map[string]string
-			err := json.Unmarshal(w.Body.Bytes(), &response)
-			assert.NoError(t, err)
-			assert.Equal(t, "V2 not implemented yet", response["error"])
+			if tt.expectedStatus == http.StatusCreated {
+				var response struct {
+					Data map[string]interface{} `json:"data"`
+				}
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, createdID.Hex(), response.Data["id"])
+				assert.Equal(t, "https://example.com/checkout", response.Data["documentURL"])
+				assert.Equal(t, "https://cdn.example.net/app.js", response.Data["blockedURL"])
+				assert.Equal(t, "203.0.113.7", response.Data["clientIP"])
+				assert.Equal(t, "/v2/reports/"+createdID.Hex(), w.Header().Get("Location"))
+			} else {
+				var response map[string]interface{}
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+				assert.Equal(t, float64(tt.expectedStatus), response["status"])
+				assert.NotContains(t, w.Body.String(), "connection reset")
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateReportV2ValidationErrors(t *testing.T) {
+	mockService := new(MockReportsService)
+	err := fmt.Errorf("%w: %w", application.ErrValidation, &domain.ValidationError{Errors: []domain.FieldError{
+		{Field: domain.FieldDocumentUri, Message: "is required"},
+		{Field: domain.FieldEffectiveDirective, Message: `unknown directive "scirpt-src"`},
+	}})
+	mockService.On("CreateReport", mock.Anything, mock.AnythingOfType("*domain.Report")).Return(err)
+	router := setupReportTestRouter(mockService)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/v2/reports", bytes.NewBufferString(`{"effectiveDirective": "scirpt-src"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"type": "about:blank",
+		"title": "Unprocessable Entity",
+		"status": 422,
+		"detail": "the report has invalid fields",
+		"instance": "/v2/reports",
+		"errors": [
+			{"field": "documentURL", "message": "is required"},
+			{"field": "effectiveDirective", "message": "unknown directive \"scirpt-src\""}
+		]
+	}`, w.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestGetReportV2(t *testing.T) {
+	testID := primitive.NewObjectID()
+
+	tests := []struct {
+		name           string
+		setupMock      func(*MockReportsService)
+		reportID       string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Success",
+			setupMock: func(m *MockReportsService) {
+				m.On("GetReport", mock.Anything, testID.Hex()).Return(&domain.Report{
+					ID: testID,
+					Report: domain.ReportData{
+						DocumentUri:        "https://example.com",
+						EffectiveDirective: "script-src",
+						ReportTime:         1700000000,
+						NoiseCategory:      domain.NoiseExtension,
+						NoiseReason:        "blocked URI belongs to a browser extension",
+					},
+				}, nil)
+			},
+			reportID:       testID.Hex(),
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"data": {
+				"id": "` + testID.Hex() + `",
+				"documentURL": "https://example.com",
+				"referrer": "",
+				"violatedDirective": "",
+				"effectiveDirective": "script-src",
+				"originalPolicy": "",
+				"disposition": "",
+				"blockedURL": "",
+				"lineNumber": 0,
+				"sourceFile": "",
+				"statusCode": 0,
+				"sample": "",
+				"clientIP": "",
+				"userAgent": "",
+				"reportTime": "2023-11-14T22:13:20Z",
+				"noiseCategory": "extension",
+				"noiseReason": "blocked URI belongs to a browser extension",
+				"fingerprint": ""
+			}}`,
+		},
+		{
+			name: "Not Found",
+			setupMock: func(m *MockReportsService) {
+				m.On("GetReport", mock.Anything, "non-existent").Return(nil, application.ErrNotFound)
+			},
+			reportID:       "non-existent",
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{
+				"type": "about:blank",
+				"title": "Not Found",
+				"status": 404,
+				"detail": "not found",
+				"instance": "/v2/reports/non-existent"
+			}`,
+		},
+		{
+			name: "Storage Unavailable",
+			setupMock: func(m *MockReportsService) {
+				err := fmt.Errorf("%w: %w", application.ErrUnavailable, errors.New("connection refused"))
+				m.On("GetReport", mock.Anything, testID.Hex()).Return(nil, err)
+			},
+			reportID:       testID.Hex(),
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody: `{
+				"type": "about:blank",
+				"title": "Service Unavailable",
+				"status": 503,
+				"detail": "storage unavailable",
+				"instance": "/v2/reports/` + testID.Hex() + `"
+			}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockReportsService)
+			tt.setupMock(mockService)
+			router := setupReportTestRouter(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/v2/reports/"+tt.reportID, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetReportPolicy(t *testing.T) {
+	testID := primitive.NewObjectID()
+
+	tests := []struct {
+		name           string
+		path           string
+		setupMock      func(*MockReportsService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "V1 Success",
+			path: "/v1/reports/" + testID.Hex() + "/policy",
+			setupMock: func(m *MockReportsService) {
+				m.On("GetReport", mock.Anything, testID.Hex()).Return(&domain.Report{
+					ID:     testID,
+					Report: domain.ReportData{OriginalPolicy: "script-src 'self' https://cdn.example.com; sandbox"},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"directives": [
+				{"name": "script-src", "sources": [
+					{"kind": "keyword", "value": "'self'"},
+					{"kind": "host", "value": "https://cdn.example.com", "scheme": "https", "host": "cdn.example.com"}
+				]},
+				{"name": "sandbox", "sources": []}
+			]}`,
+		},
+		{
+			name: "V1 Without Policy",
+			path: "/v1/reports/" + testID.Hex() + "/policy",
+			setupMock: func(m *MockReportsService) {
+				m.On("GetReport", mock.Anything, testID.Hex()).Return(&domain.Report{ID: testID}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"directives": []}`,
+		},
+		{
+			name: "V1 Not Found",
+			path: "/v1/reports/" + testID.Hex() + "/policy",
+			setupMock: func(m *MockReportsService) {
+				m.On("GetReport", mock.Anything, testID.Hex()).Return(nil, application.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error": "not found"}`,
+		},
+		{
+			name: "V2 Success",
+			path: "/v2/reports/" + testID.Hex() + "/policy",
+			setupMock: func(m *MockReportsService) {
+				m.On("GetReport", mock.Anything, testID.Hex()).Return(&domain.Report{
+					ID:     testID,
+					Report: domain.ReportData{OriginalPolicy: "img-src data:"},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"data": {"directives": [
+				{"name": "img-src", "sources": [{"kind": "scheme", "value": "data:", "scheme": "data"}]}
+			]}}`,
+		},
+		{
+			name: "V2 Invalid ID",
+			path: "/v2/reports/not-an-id/policy",
+			setupMock: func(m *MockReportsService) {
+				m.On("GetReport", mock.Anything, "not-an-id").Return(nil, application.ErrInvalidID)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"detail": "invalid ID",
+				"instance": "/v2/reports/not-an-id/policy"
+			}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockReportsService)
+			tt.setupMock(mockService)
+			router := setupReportTestRouter(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", tt.path, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListReportsV2(t *testing.T) {
+	testID := primitive.NewObjectID()
+	total := int64(2)
+	next := &application.ReportCursor{ReportTime: 1700000000, ID: testID.Hex()}
+
+	tests := []struct {
+		name           string
+		setupMock      func(*MockReportsService)
+		query          string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Success",
+			setupMock: func(m *MockReportsService) {
+				m.On("ListReports", mock.Anything, application.ReportQuery{
+					Filter: application.ReportFilter{
+						BlockedUri: &application.StringFilter{Value: "https://cdn.example.net", Match: application.MatchPrefix},
+					},
+					Limit:        1,
+					Sort:         application.SortNewest,
+					IncludeTotal: true,
+				}).Return(&application.ReportPage{
+					Reports: []domain.Report{{ID: testID, Report: domain.ReportData{ReportTime: 1700000000}}},
+					Next:    next,
+					Total:   &total,
+				}, nil)
+			},
+			query:          "?blockedURLPrefix=https://cdn.example.net&limit=1&total=true",
+			expectedStatus: http.StatusOK,
+			expectedBody: `{
+				"data": [{
+					"id": "` + testID.Hex() + `",
+					"documentURL": "",
+					"referrer": "",
+					"violatedDirective": "",
+					"effectiveDirective": "",
+					"originalPolicy": "",
+					"disposition": "",
+					"blockedURL": "",
+					"lineNumber": 0,
+					"sourceFile": "",
+					"statusCode": 0,
+					"sample": "",
+					"clientIP": "",
+					"userAgent": "",
+					"reportTime": "2023-11-14T22:13:20Z",
+					"noiseCategory": "",
+					"noiseReason": "",
+					"fingerprint": ""
+				}],
+				"meta": {
+					"limit": 1,
+					"nextCursor": "` + next.Encode() + `",
+					"next": "/v2/reports?blockedURLPrefix=https%3A%2F%2Fcdn.example.net&cursor=` + next.Encode() + `&limit=1&total=true",
+					"total": 2
+				}
+			}`,
+		},
+		{
+			name:           "Invalid Cursor",
+			setupMock:      func(m *MockReportsService) {},
+			query:          "?cursor=not-a-cursor",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"detail": "invalid cursor",
+				"instance": "/v2/reports"
+			}`,
+		},
+		{
+			name: "Service Error",
+			setupMock: func(m *MockReportsService) {
+				m.On("ListReports", mock.Anything, mock.Anything).Return(nil, errors.New("service error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: `{
+				"type": "about:blank",
+				"title": "Internal Server Error",
+				"status": 500,
+				"instance": "/v2/reports"
+			}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockReportsService)
+			tt.setupMock(mockService)
+			router := setupReportTestRouter(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/v2/reports"+tt.query, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+
+			mockService.AssertExpectations(t)
 		})
 	}
 }
+
+func TestReportRoutesIngestHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	limits := Limits{Ingest: []gin.HandlerFunc{func(c *gin.Context) {
+		c.AbortWithStatus(http.StatusTooManyRequests)
+	}}}
+	service := new(MockReportsService)
+	service.On("ListReports", mock.Anything, mock.Anything).Return(&application.ReportPage{Reports: []domain.Report{}}, nil)
+	setupReportRoutesV1(router.Group("/v1"), service, limits)
+	setupReportRoutesV2(router.Group("/v2"), service, limits)
+
+	for _, path := range []string{"/v1/reports", "/v1/reports/csp-report", "/v1/reports/reporting-api", "/v2/reports"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+		assert.Equal(t, http.StatusTooManyRequests, w.Code, path)
+	}
+
+	// Reads don't run the ingest handlers
+	for _, path := range []string{"/v1/reports", "/v2/reports"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusOK, w.Code, path)
+	}
+}
+
+func TestIngestPaths(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	service := new(MockReportsService)
+	setupReportRoutesV1(router.Group("/api/v1"), service, Limits{})
+	setupReportRoutesV2(router.Group("/api/v2"), service, Limits{})
+
+	var posts []string
+	for _, route := range router.Routes() {
+		if route.Method == http.MethodPost {
+			posts = append(posts, route.Path)
+		}
+	}
+	assert.ElementsMatch(t, posts, IngestPaths)
+}
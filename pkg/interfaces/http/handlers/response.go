@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
+	"github.com/AchimGrolimund/CSP-Scout-API/pkg/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// envelope is the V2 response body wrapping every successful response
+type envelope struct {
+	Data interface{} `json:"data"`
+	Meta interface{} `json:"meta,omitempty"`
+}
+
+// pageMeta describes the pagination state of a V2 list response
+type pageMeta struct {
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	Next       string `json:"next,omitempty"`
+	Total      *int64 `json:"total,omitempty"`
+}
+
+// problem is an RFC 7807 problem details body returned by V2 on errors
+type problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// Errors lists the rejected fields of an invalid report
+	Errors []domain.FieldError `json:"errors,omitempty"`
+}
+
+// problemContentType is the media type of RFC 7807 problem details
+const problemContentType = "application/problem+json"
+
+// respondV2 writes data, and optional meta, in the V2 envelope
+func respondV2(c *gin.Context, status int, data interface{}, meta interface{}) {
+	c.JSON(status, envelope{Data: data, Meta: meta})
+}
+
+// respondProblem writes an RFC 7807 problem for the status and aborts the request
+func respondProblem(c *gin.Context, status int, detail string) {
+	c.Header("Content-Type", problemContentType)
+	c.AbortWithStatusJSON(status, problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: c.Request.URL.Path,
+	})
+}
+
+// bindStatus maps an error decoding the request body to its status. Bodies
+// cut off by the size limit are too large; anything else is malformed.
+func bindStatus(err error) int {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+// errorStatus maps an application error to the HTTP status and the message
+// shown to clients. Only validation errors expose their details; unexpected
+// errors and unavailable storage are recorded for the request log instead.
+func errorStatus(c *gin.Context, err error) (int, string) {
+	switch {
+	case errors.Is(err, application.ErrInvalidID):
+		return http.StatusBadRequest, application.ErrInvalidID.Error()
+	case errors.Is(err, application.ErrInvalidCursor):
+		return http.StatusBadRequest, application.ErrInvalidCursor.Error()
+	case errors.Is(err, application.ErrNotFound):
+		return http.StatusNotFound, application.ErrNotFound.Error()
+	case errors.Is(err, application.ErrValidation):
+		return http.StatusUnprocessableEntity, err.Error()
+	case errors.Is(err, application.ErrUnavailable):
+		_ = c.Error(err)
+		c.Header("Retry-After", "5")
+		return http.StatusServiceUnavailable, application.ErrUnavailable.Error()
+	default:
+		_ = c.Error(err)
+		return http.StatusInternalServerError, ""
+	}
+}
+
+// respondError writes the V1 error body for an application error. Invalid
+// reports also list their rejected fields.
+func respondError(c *gin.Context, err error) {
+	status, message := errorStatus(c, err)
+	if message == "" {
+		message = strings.ToLower(http.StatusText(status))
+	}
+
+	body := gin.H{"error": message}
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		body["fields"] = validationErr.Errors
+	}
+	c.AbortWithStatusJSON(status, body)
+}
+
+// respondErrorProblem writes the RFC 7807 problem for an application error.
+// Invalid reports list their rejected fields under their V2 names.
+func respondErrorProblem(c *gin.Context, err error) {
+	status, detail := errorStatus(c, err)
+
+	var validationErr *domain.ValidationError
+	if !errors.As(err, &validationErr) {
+		respondProblem(c, status, detail)
+		return
+	}
+
+	fieldErrs := make([]domain.FieldError, len(validationErr.Errors))
+	for i, fieldErr := range validationErr.Errors {
+		fieldErrs[i] = domain.FieldError{Field: v2FieldNames.name(fieldErr.Field), Message: fieldErr.Message}
+	}
+	c.Header("Content-Type", problemContentType)
+	c.AbortWithStatusJSON(status, problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   "the report has invalid fields",
+		Instance: c.Request.URL.Path,
+		Errors:   fieldErrs,
+	})
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/AchimGrolimund/CSP-Scout-API/pkg/application"
@@ -24,24 +25,40 @@ func setupStatisticsRoutesV1(router *gin.RouterGroup, service application.Statis
 	{
 		stats.GET("/top-ips", handler.GetTopIPsV1)
 		stats.GET("/top-directives", handler.GetTopViolatedDirectivesV1)
+		stats.GET("/top-effective-directives", handler.GetTopEffectiveDirectivesV1)
+		stats.GET("/top-blocked-uris", handler.GetTopBlockedURIsV1)
+		stats.GET("/top-documents", handler.GetTopDocumentsV1)
+		stats.GET("/top-source-files", handler.GetTopSourceFilesV1)
+		stats.GET("/timeseries", handler.GetTimeSeriesV1)
 	}
 }
 
-// V2 Routes (for future implementation)
+// V2 Routes
 func setupStatisticsRoutesV2(router *gin.RouterGroup, service application.StatisticsService) {
 	handler := NewStatisticsHandler(service)
 	stats := router.Group("/statistics")
 	{
 		stats.GET("/top-ips", handler.GetTopIPsV2)
 		stats.GET("/top-directives", handler.GetTopViolatedDirectivesV2)
+		stats.GET("/top-effective-directives", handler.GetTopEffectiveDirectivesV2)
+		stats.GET("/top-blocked-uris", handler.GetTopBlockedURIsV2)
+		stats.GET("/top-documents", handler.GetTopDocumentsV2)
+		stats.GET("/top-source-files", handler.GetTopSourceFilesV2)
+		stats.GET("/timeseries", handler.GetTimeSeriesV2)
 	}
 }
 
 // V1 Handlers
 func (h *StatisticsHandler) GetTopIPsV1(c *gin.Context) {
-	topIPs, err := h.service.GetTopIPs(c.Request.Context())
+	params, err := parseStatisticsParams(c, v1QueryNames)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	topIPs, err := h.service.GetTopIPs(c.Request.Context(), params)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -49,22 +66,228 @@ func (h *StatisticsHandler) GetTopIPsV1(c *gin.Context) {
 }
 
 func (h *StatisticsHandler) GetTopViolatedDirectivesV1(c *gin.Context) {
-	topDirectives, err := h.service.GetTopViolatedDirectives(c.Request.Context())
+	params, err := parseStatisticsParams(c, v1QueryNames)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	topDirectives, err := h.service.GetTopViolatedDirectives(c.Request.Context(), params)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, topDirectives)
 }
 
-// V2 Handlers (for future implementation)
+func (h *StatisticsHandler) GetTopEffectiveDirectivesV1(c *gin.Context) {
+	params, err := parseStatisticsParams(c, v1QueryNames)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	topDirectives, err := h.service.GetTopEffectiveDirectives(c.Request.Context(), params)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, topDirectives)
+}
+
+// GetTopBlockedURIsV1 returns the most frequently blocked URIs, grouped by origin with normalize=origin
+func (h *StatisticsHandler) GetTopBlockedURIsV1(c *gin.Context) {
+	params, err := parseURIStatisticsParams(c, v1QueryNames)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	topBlockedURIs, err := h.service.GetTopBlockedURIs(c.Request.Context(), params)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, topBlockedURIs)
+}
+
+func (h *StatisticsHandler) GetTopDocumentsV1(c *gin.Context) {
+	params, err := parseStatisticsParams(c, v1QueryNames)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	topDocuments, err := h.service.GetTopDocuments(c.Request.Context(), params)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, topDocuments)
+}
+
+// GetTopSourceFilesV1 returns the most frequent source files, grouped by origin with normalize=origin
+func (h *StatisticsHandler) GetTopSourceFilesV1(c *gin.Context) {
+	params, err := parseURIStatisticsParams(c, v1QueryNames)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	topSourceFiles, err := h.service.GetTopSourceFiles(c.Request.Context(), params)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, topSourceFiles)
+}
+
+// GetTimeSeriesV1 returns the number of reports per time bucket, zero-filled over the requested range
+func (h *StatisticsHandler) GetTimeSeriesV1(c *gin.Context) {
+	params, err := parseTimeSeriesParams(c, v1QueryNames)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	timeSeries, err := h.service.GetTimeSeries(c.Request.Context(), params)
+	if isTimeSeriesParamsError(err) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, timeSeries)
+}
+
+// V2 Handlers
 func (h *StatisticsHandler) GetTopIPsV2(c *gin.Context) {
-	// Implement V2 top IPs logic when needed
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "V2 not implemented yet"})
+	params, err := parseStatisticsParams(c, v2QueryNames)
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results, err := h.service.GetTopIPs(c.Request.Context(), params)
+	if err != nil {
+		respondErrorProblem(c, err)
+		return
+	}
+
+	respondV2(c, http.StatusOK, newTopIPsV2(results), nil)
 }
 
 func (h *StatisticsHandler) GetTopViolatedDirectivesV2(c *gin.Context) {
-	// Implement V2 top directives logic when needed
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "V2 not implemented yet"})
+	params, err := parseStatisticsParams(c, v2QueryNames)
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results, err := h.service.GetTopViolatedDirectives(c.Request.Context(), params)
+	if err != nil {
+		respondErrorProblem(c, err)
+		return
+	}
+
+	respondV2(c, http.StatusOK, newTopDirectivesV2("violatedDirective", results), nil)
+}
+
+func (h *StatisticsHandler) GetTopEffectiveDirectivesV2(c *gin.Context) {
+	params, err := parseStatisticsParams(c, v2QueryNames)
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results, err := h.service.GetTopEffectiveDirectives(c.Request.Context(), params)
+	if err != nil {
+		respondErrorProblem(c, err)
+		return
+	}
+
+	respondV2(c, http.StatusOK, newTopDirectivesV2("effectiveDirective", results), nil)
+}
+
+func (h *StatisticsHandler) GetTopBlockedURIsV2(c *gin.Context) {
+	params, err := parseURIStatisticsParams(c, v2QueryNames)
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results, err := h.service.GetTopBlockedURIs(c.Request.Context(), params)
+	if err != nil {
+		respondErrorProblem(c, err)
+		return
+	}
+
+	respondV2(c, http.StatusOK, newTopBlockedURIsV2(results), nil)
+}
+
+func (h *StatisticsHandler) GetTopDocumentsV2(c *gin.Context) {
+	params, err := parseStatisticsParams(c, v2QueryNames)
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results, err := h.service.GetTopDocuments(c.Request.Context(), params)
+	if err != nil {
+		respondErrorProblem(c, err)
+		return
+	}
+
+	respondV2(c, http.StatusOK, newTopDocumentsV2(results), nil)
+}
+
+func (h *StatisticsHandler) GetTopSourceFilesV2(c *gin.Context) {
+	params, err := parseURIStatisticsParams(c, v2QueryNames)
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results, err := h.service.GetTopSourceFiles(c.Request.Context(), params)
+	if err != nil {
+		respondErrorProblem(c, err)
+		return
+	}
+
+	respondV2(c, http.StatusOK, newTopSourceFilesV2(results), nil)
+}
+
+func (h *StatisticsHandler) GetTimeSeriesV2(c *gin.Context) {
+	params, err := parseTimeSeriesParams(c, v2QueryNames)
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	timeSeries, err := h.service.GetTimeSeries(c.Request.Context(), params)
+	if isTimeSeriesParamsError(err) {
+		respondProblem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		respondErrorProblem(c, err)
+		return
+	}
+
+	respondV2(c, http.StatusOK, newTimeSeriesV2(timeSeries), nil)
+}
+
+// isTimeSeriesParamsError reports whether the service rejected the time series
+// parameters, which is the client's fault
+func isTimeSeriesParamsError(err error) bool {
+	return errors.Is(err, application.ErrTooManyBuckets) || errors.Is(err, application.ErrInvalidBucket) ||
+		errors.Is(err, application.ErrInvalidGroupBy) || errors.Is(err, application.ErrInvalidRange)
 }
@@ -19,22 +19,62 @@ type MockStatisticsService struct {
 	mock.Mock
 }
 
-func (m *MockStatisticsService) GetTopIPs(ctx context.Context) ([]application.TopIPResult, error) {
-	args := m.Called(ctx)
+func (m *MockStatisticsService) GetTopIPs(ctx context.Context, params application.StatisticsParams) ([]application.TopIPResult, error) {
+	args := m.Called(ctx, params)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]application.TopIPResult), args.Error(1)
 }
 
-func (m *MockStatisticsService) GetTopViolatedDirectives(ctx context.Context) ([]application.TopDirectiveResult, error) {
-	args := m.Called(ctx)
+func (m *MockStatisticsService) GetTopViolatedDirectives(ctx context.Context, params application.StatisticsParams) ([]application.TopDirectiveResult, error) {
+	args := m.Called(ctx, params)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]application.TopDirectiveResult), args.Error(1)
 }
 
+func (m *MockStatisticsService) GetTopEffectiveDirectives(ctx context.Context, params application.StatisticsParams) ([]application.TopDirectiveResult, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]application.TopDirectiveResult), args.Error(1)
+}
+
+func (m *MockStatisticsService) GetTopBlockedURIs(ctx context.Context, params application.StatisticsParams) ([]application.TopBlockedURIResult, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]application.TopBlockedURIResult), args.Error(1)
+}
+
+func (m *MockStatisticsService) GetTopDocuments(ctx context.Context, params application.StatisticsParams) ([]application.TopDocumentResult, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]application.TopDocumentResult), args.Error(1)
+}
+
+func (m *MockStatisticsService) GetTopSourceFiles(ctx context.Context, params application.StatisticsParams) ([]application.TopSourceFileResult, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]application.TopSourceFileResult), args.Error(1)
+}
+
+func (m *MockStatisticsService) GetTimeSeries(ctx context.Context, params application.TimeSeriesParams) (*application.TimeSeries, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*application.TimeSeries), args.Error(1)
+}
+
 func setupTestRouter(service application.StatisticsService) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
@@ -49,13 +89,14 @@ func TestGetTopIPsV1(t *testing.T) {
 	tests := []struct {
 		name           string
 		setupMock      func(*MockStatisticsService)
+		query          string
 		expectedStatus int
 		expectedBody   interface{}
 	}{
 		{
 			name: "Success",
 			setupMock: func(m *MockStatisticsService) {
-				m.On("GetTopIPs", mock.Anything).Return([]application.TopIPResult{
+				m.On("GetTopIPs", mock.Anything, application.StatisticsParams{}).Return([]application.TopIPResult{
 					{IP: "192.168.1.1", Count: 10},
 					{IP: "192.168.1.2", Count: 5},
 				}, nil)
@@ -66,13 +107,44 @@ func TestGetTopIPsV1(t *testing.T) {
 				{IP: "192.168.1.2", Count: 5},
 			},
 		},
+		{
+			name: "Parameters",
+			setupMock: func(m *MockStatisticsService) {
+				m.On("GetTopIPs", mock.Anything, application.StatisticsParams{
+					Limit: 5,
+					Filter: application.ReportFilter{
+						Disposition: &application.StringFilter{Value: "enforce", Match: application.MatchExact},
+						DocumentUri: &application.StringFilter{Value: "https://example.com/", Match: application.MatchPrefix},
+						From:        1700000000,
+						To:          1700086400,
+					},
+				}).Return([]application.TopIPResult{{IP: "192.168.1.1", Count: 3}}, nil)
+			},
+			query:          "?limit=5&disposition=enforce&documenturi_prefix=https://example.com/&from=1700000000&to=1700086400",
+			expectedStatus: http.StatusOK,
+			expectedBody:   []application.TopIPResult{{IP: "192.168.1.1", Count: 3}},
+		},
+		{
+			name:           "Invalid Limit",
+			setupMock:      func(m *MockStatisticsService) {},
+			query:          "?limit=5000",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   gin.H{"error": `invalid limit "5000": must be between 1 and 1000`},
+		},
+		{
+			name:           "Invalid Time",
+			setupMock:      func(m *MockStatisticsService) {},
+			query:          "?from=yesterday",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   gin.H{"error": `invalid from "yesterday": must be Unix seconds or an RFC 3339 time`},
+		},
 		{
 			name: "Service Error",
 			setupMock: func(m *MockStatisticsService) {
-				m.On("GetTopIPs", mock.Anything).Return(nil, errors.New("service error"))
+				m.On("GetTopIPs", mock.Anything, application.StatisticsParams{}).Return(nil, errors.New("service error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   gin.H{"error": "service error"},
+			expectedBody:   gin.H{"error": "internal server error"},
 		},
 	}
 
@@ -83,7 +155,7 @@ func TestGetTopIPsV1(t *testing.T) {
 			router := setupTestRouter(mockService)
 
 			w := httptest.NewRecorder()
-			req, _ := http.NewRequest("GET", "/v1/statistics/top-ips", nil)
+			req, _ := http.NewRequest("GET", "/v1/statistics/top-ips"+tt.query, nil)
 			router.ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
@@ -111,7 +183,7 @@ func TestGetTopViolatedDirectivesV1(t *testing.T) {
 		{
 			name: "Success",
 			setupMock: func(m *MockStatisticsService) {
-				m.On("GetTopViolatedDirectives", mock.Anything).Return([]application.TopDirectiveResult{
+				m.On("GetTopViolatedDirectives", mock.Anything, application.StatisticsParams{}).Return([]application.TopDirectiveResult{
 					{Directive: "script-src", Count: 15},
 					{Directive: "style-src", Count: 8},
 				}, nil)
@@ -125,10 +197,10 @@ func TestGetTopViolatedDirectivesV1(t *testing.T) {
 		{
 			name: "Service Error",
 			setupMock: func(m *MockStatisticsService) {
-				m.On("GetTopViolatedDirectives", mock.Anything).Return(nil, errors.New("service error"))
+				m.On("GetTopViolatedDirectives", mock.Anything, application.StatisticsParams{}).Return(nil, errors.New("service error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   gin.H{"error": "service error"},
+			expectedBody:   gin.H{"error": "internal server error"},
 		},
 	}
 
@@ -157,36 +229,337 @@ func TestGetTopViolatedDirectivesV1(t *testing.T) {
 	}
 }
 
-func TestV2Endpoints(t *testing.T) {
-	mockService := new(MockStatisticsService)
-	router := setupTestRouter(mockService)
+func TestTopReportFieldsV1(t *testing.T) {
+	tests := []struct {
+		name           string
+		endpoint       string
+		setupMock      func(*MockStatisticsService)
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:     "Top Effective Directives",
+			endpoint: "/v1/statistics/top-effective-directives",
+			setupMock: func(m *MockStatisticsService) {
+				m.On("GetTopEffectiveDirectives", mock.Anything, application.StatisticsParams{}).
+					Return([]application.TopDirectiveResult{{Directive: "script-src-elem", Count: 4}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   []application.TopDirectiveResult{{Directive: "script-src-elem", Count: 4}},
+		},
+		{
+			name:     "Top Blocked URIs By Origin",
+			endpoint: "/v1/statistics/top-blocked-uris?normalize=origin&limit=3",
+			setupMock: func(m *MockStatisticsService) {
+				m.On("GetTopBlockedURIs", mock.Anything, application.StatisticsParams{Limit: 3, NormalizeOrigin: true}).
+					Return([]application.TopBlockedURIResult{{BlockedURI: "https://cdn.example.net", Count: 7}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   []application.TopBlockedURIResult{{BlockedURI: "https://cdn.example.net", Count: 7}},
+		},
+		{
+			name:           "Top Blocked URIs Invalid Normalization",
+			endpoint:       "/v1/statistics/top-blocked-uris?normalize=host",
+			setupMock:      func(m *MockStatisticsService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   gin.H{"error": `invalid normalize "host": must be "origin"`},
+		},
+		{
+			name:           "Top Documents Normalization Unsupported",
+			endpoint:       "/v1/statistics/top-documents?normalize=origin",
+			setupMock:      func(m *MockStatisticsService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   gin.H{"error": `invalid normalize "origin": only supported for blocked URIs and source files`},
+		},
+		{
+			name:     "Top Documents",
+			endpoint: "/v1/statistics/top-documents",
+			setupMock: func(m *MockStatisticsService) {
+				m.On("GetTopDocuments", mock.Anything, application.StatisticsParams{}).
+					Return([]application.TopDocumentResult{{DocumentURI: "https://example.com/checkout", Count: 12}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   []application.TopDocumentResult{{DocumentURI: "https://example.com/checkout", Count: 12}},
+		},
+		{
+			name:     "Top Source Files Service Error",
+			endpoint: "/v1/statistics/top-source-files",
+			setupMock: func(m *MockStatisticsService) {
+				m.On("GetTopSourceFiles", mock.Anything, application.StatisticsParams{}).Return(nil, errors.New("service error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   gin.H{"error": "internal server error"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockStatisticsService)
+			tt.setupMock(mockService)
+			router := setupTestRouter(mockService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", tt.endpoint, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			expectedJSON, _ := json.Marshal(tt.expectedBody)
+			assert.JSONEq(t, string(expectedJSON), w.Body.String())
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetTimeSeriesV1(t *testing.T) {
+	timeSeries := &application.TimeSeries{
+		Bucket:  application.BucketHour,
+		From:    1699999200,
+		To:      1700006400,
+		GroupBy: "disposition",
+		Series: []application.TimeSeriesGroup{
+			{Group: "enforce", Points: []application.TimeSeriesPoint{{Time: 1699999200, Count: 3}, {Time: 1700002800}}},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(*MockStatisticsService)
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name:  "Success",
+			query: "?bucket=hour&groupby=disposition&from=1699999200&to=1700006400",
+			setupMock: func(m *MockStatisticsService) {
+				m.On("GetTimeSeries", mock.Anything, application.TimeSeriesParams{
+					Bucket:  application.BucketHour,
+					GroupBy: "disposition",
+					Filter:  application.ReportFilter{From: 1699999200, To: 1700006400},
+				}).Return(timeSeries, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   timeSeries,
+		},
+		{
+			name:           "Invalid Bucket",
+			query:          "?bucket=week",
+			setupMock:      func(m *MockStatisticsService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   gin.H{"error": `invalid bucket "week": must be "minute", "hour" or "day"`},
+		},
+		{
+			name:           "Invalid Group By",
+			query:          "?groupby=clientip",
+			setupMock:      func(m *MockStatisticsService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   gin.H{"error": `invalid groupby "clientip": must be one of violateddirective, effectivedirective, disposition`},
+		},
+		{
+			name:  "Too Many Buckets",
+			query: "?bucket=minute&since=720h",
+			setupMock: func(m *MockStatisticsService) {
+				m.On("GetTimeSeries", mock.Anything, mock.Anything).Return(nil, application.ErrTooManyBuckets)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   gin.H{"error": application.ErrTooManyBuckets.Error()},
+		},
+		{
+			name:  "Future From",
+			query: "?from=4102444800",
+			setupMock: func(m *MockStatisticsService) {
+				m.On("GetTimeSeries", mock.Anything, application.TimeSeriesParams{
+					Filter: application.ReportFilter{From: 4102444800},
+				}).Return(nil, application.ErrInvalidRange)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   gin.H{"error": application.ErrInvalidRange.Error()},
+		},
+		{
+			name:  "Service Error",
+			query: "",
+			setupMock: func(m *MockStatisticsService) {
+				m.On("GetTimeSeries", mock.Anything, application.TimeSeriesParams{}).Return(nil, errors.New("service error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   gin.H{"error": "internal server error"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockStatisticsService)
+			tt.setupMock(mockService)
+			router := setupTestRouter(mockService)
 
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/v1/statistics/timeseries"+tt.query, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			expectedJSON, _ := json.Marshal(tt.expectedBody)
+			assert.JSONEq(t, string(expectedJSON), w.Body.String())
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestStatisticsV2(t *testing.T) {
 	tests := []struct {
-		name     string
-		endpoint string
+		name           string
+		endpoint       string
+		setupMock      func(*MockStatisticsService)
+		expectedStatus int
+		expectedBody   string
 	}{
 		{
-			name:     "GetTopIPsV2",
-			endpoint: "/v2/statistics/top-ips",
+			name:     "Top IPs",
+			endpoint: "/v2/statistics/top-ips?limit=2&documentURLPrefix=https://example.com/",
+			setupMock: func(m *MockStatisticsService) {
+				m.On("GetTopIPs", mock.Anything, application.StatisticsParams{
+					Limit: 2,
+					Filter: application.ReportFilter{
+						DocumentUri: &application.StringFilter{Value: "https://example.com/", Match: application.MatchPrefix},
+					},
+				}).Return([]application.TopIPResult{{IP: "192.168.1.1", Count: 10}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"data": {"field": "clientIP", "entries": [{"value": "192.168.1.1", "count": 10}]}}`,
 		},
 		{
-			name:     "GetTopViolatedDirectivesV2",
+			name:     "Top Directives",
 			endpoint: "/v2/statistics/top-directives",
+			setupMock: func(m *MockStatisticsService) {
+				m.On("GetTopViolatedDirectives", mock.Anything, application.StatisticsParams{}).
+					Return([]application.TopDirectiveResult{{Directive: "script-src", Count: 15}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"data": {"field": "violatedDirective", "entries": [{"value": "script-src", "count": 15}]}}`,
+		},
+		{
+			name:     "Top Blocked URIs",
+			endpoint: "/v2/statistics/top-blocked-uris?normalize=origin",
+			setupMock: func(m *MockStatisticsService) {
+				m.On("GetTopBlockedURIs", mock.Anything, application.StatisticsParams{NormalizeOrigin: true}).
+					Return([]application.TopBlockedURIResult{{BlockedURI: "https://cdn.example.net", Count: 7}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"data": {"field": "blockedURL", "entries": [{"value": "https://cdn.example.net", "count": 7}]}}`,
+		},
+		{
+			name:     "Top Source Files",
+			endpoint: "/v2/statistics/top-source-files?normalize=origin",
+			setupMock: func(m *MockStatisticsService) {
+				m.On("GetTopSourceFiles", mock.Anything, application.StatisticsParams{NormalizeOrigin: true}).
+					Return([]application.TopSourceFileResult{{SourceFile: "https://cdn.example.net", Count: 3}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"data": {"field": "sourceFile", "entries": [{"value": "https://cdn.example.net", "count": 3}]}}`,
+		},
+		{
+			name:           "Top IPs Normalization Unsupported",
+			endpoint:       "/v2/statistics/top-ips?normalize=origin",
+			setupMock:      func(m *MockStatisticsService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"detail": "invalid normalize \"origin\": only supported for blocked URIs and source files",
+				"instance": "/v2/statistics/top-ips"
+			}`,
+		},
+		{
+			name:     "Time Series",
+			endpoint: "/v2/statistics/timeseries?bucket=day&groupBy=effectiveDirective&from=1699920000&to=1700006400",
+			setupMock: func(m *MockStatisticsService) {
+				m.On("GetTimeSeries", mock.Anything, application.TimeSeriesParams{
+					Bucket:  application.BucketDay,
+					GroupBy: "effectivedirective",
+					Filter:  application.ReportFilter{From: 1699920000, To: 1700006400},
+				}).Return(&application.TimeSeries{
+					Bucket:  application.BucketDay,
+					From:    1699920000,
+					To:      1700006400,
+					GroupBy: "effectivedirective",
+					Series: []application.TimeSeriesGroup{
+						{Group: "img-src", Points: []application.TimeSeriesPoint{{Time: 1699920000, Count: 2}}},
+					},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"data": {
+				"bucket": "day",
+				"from": "2023-11-14T00:00:00Z",
+				"to": "2023-11-15T00:00:00Z",
+				"groupBy": "effectiveDirective",
+				"series": [{"group": "img-src", "points": [{"time": "2023-11-14T00:00:00Z", "count": 2}]}]
+			}}`,
+		},
+		{
+			name:     "Time Series Future From",
+			endpoint: "/v2/statistics/timeseries?from=4102444800",
+			setupMock: func(m *MockStatisticsService) {
+				m.On("GetTimeSeries", mock.Anything, application.TimeSeriesParams{
+					Filter: application.ReportFilter{From: 4102444800},
+				}).Return(nil, application.ErrInvalidRange)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"detail": "invalid time range: from must be before to",
+				"instance": "/v2/statistics/timeseries"
+			}`,
+		},
+		{
+			name:           "Invalid Limit",
+			endpoint:       "/v2/statistics/top-documents?limit=0",
+			setupMock:      func(m *MockStatisticsService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"detail": "invalid limit \"0\": must be between 1 and 1000",
+				"instance": "/v2/statistics/top-documents"
+			}`,
+		},
+		{
+			name:     "Service Error",
+			endpoint: "/v2/statistics/top-source-files",
+			setupMock: func(m *MockStatisticsService) {
+				m.On("GetTopSourceFiles", mock.Anything, application.StatisticsParams{}).Return(nil, errors.New("service error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: `{
+				"type": "about:blank",
+				"title": "Internal Server Error",
+				"status": 500,
+				"instance": "/v2/statistics/top-source-files"
+			}`,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockStatisticsService)
+			tt.setupMock(mockService)
+			router := setupTestRouter(mockService)
+
 			w := httptest.NewRecorder()
 			req, _ := http.NewRequest("GET", tt.endpoint, nil)
 			router.ServeHTTP(w, req)
 
-			assert.Equal(t, http.StatusNotImplemented, w.Code)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
 
-			var response map[string]string
-			err := json.Unmarshal(w.Body.Bytes(), &response)
-			assert.NoError(t, err)
-			assert.Equal(t, "V2 not implemented yet", response["error"])
+			mockService.AssertExpectations(t)
 		})
 	}
 }
@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(LimitBody(8))
+	router.POST("/echo", func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.Status(http.StatusRequestEntityTooLarge)
+			return
+		}
+		c.String(http.StatusOK, string(body))
+	})
+
+	tests := []struct {
+		name           string
+		body           string
+		unknownLength  bool
+		expectedStatus int
+	}{
+		{
+			name:           "Within limit",
+			body:           "12345678",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Announced length over limit",
+			body:           "123456789",
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "Streamed body over limit",
+			body:           "123456789",
+			unknownLength:  true,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(tt.body))
+			if tt.unknownLength {
+				req.ContentLength = -1
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestRateLimiterRefillsOverTime(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	limiter := NewRateLimiter(2, 3)
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		ok, _ := limiter.Allow("203.0.113.1")
+		assert.True(t, ok, "request %d within burst", i)
+	}
+	ok, wait := limiter.Allow("203.0.113.1")
+	assert.False(t, ok)
+	assert.Equal(t, 500*time.Millisecond, wait)
+
+	// Clients have separate buckets
+	ok, _ = limiter.Allow("203.0.113.2")
+	assert.True(t, ok)
+
+	now = now.Add(500 * time.Millisecond)
+	ok, _ = limiter.Allow("203.0.113.1")
+	assert.True(t, ok)
+	ok, _ = limiter.Allow("203.0.113.1")
+	assert.False(t, ok)
+}
+
+func TestRateLimiterForgetsRefilledClients(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	limiter := NewRateLimiter(1, 1)
+	limiter.now = func() time.Time { return now }
+
+	limiter.Allow("203.0.113.1")
+	now = now.Add(sweepInterval)
+	limiter.Allow("203.0.113.2")
+
+	assert.Len(t, limiter.buckets, 1)
+	assert.Contains(t, limiter.buckets, "203.0.113.2")
+}
+
+func TestRateLimiterEvictsLeastRecentClients(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	limiter := NewRateLimiter(1, 1)
+	limiter.maxClients = 2
+	limiter.now = func() time.Time { return now }
+
+	limiter.Allow("203.0.113.1")
+	limiter.Allow("203.0.113.2")
+	// The first client is seen again, so the second is the least recent
+	ok, _ := limiter.Allow("203.0.113.1")
+	assert.False(t, ok)
+	limiter.Allow("203.0.113.3")
+
+	assert.Len(t, limiter.buckets, 2)
+	assert.Equal(t, 2, limiter.recent.Len())
+	assert.NotContains(t, limiter.buckets, "203.0.113.2")
+	// The remaining clients keep their balance
+	ok, _ = limiter.Allow("203.0.113.1")
+	assert.False(t, ok)
+}
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/reports", RateLimit(NewRateLimiter(1, 1)), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/reports", nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/reports", nil))
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.JSONEq(t, `{"error":"rate limit exceeded"}`, w.Body.String())
+}
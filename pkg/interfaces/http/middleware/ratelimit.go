@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"container/list"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// sweepInterval is how often the limiter forgets clients whose bucket has refilled
+	sweepInterval = time.Minute
+	// maxClients is the number of buckets beyond which the least recently
+	// seen clients are forgotten
+	maxClients = 100_000
+)
+
+// RateLimiter is a token bucket per client. Each bucket holds up to burst
+// tokens and refills at rate tokens per second; every request takes one.
+type RateLimiter struct {
+	rate       float64
+	burst      float64
+	maxClients int
+	now        func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*list.Element
+	// recent orders the buckets by their last request, most recent first
+	recent    *list.List
+	lastSweep time.Time
+}
+
+// bucket is the token balance of a client at the time it was last updated
+type bucket struct {
+	key     string
+	tokens  float64
+	updated time.Time
+}
+
+// NewRateLimiter creates a limiter allowing each client rate requests per
+// second with bursts of up to burst requests
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		rate:       rate,
+		burst:      float64(burst),
+		maxClients: maxClients,
+		now:        time.Now,
+		buckets:    make(map[string]*list.Element),
+		recent:     list.New(),
+	}
+}
+
+// Allow takes a token from the bucket of key. If the bucket is empty the
+// request is refused and Allow returns how long until a token is available.
+// Once the limiter holds maxClients buckets, a new client replaces the least
+// recently seen one, which starts over with a full bucket if it returns.
+func (l *RateLimiter) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	e, ok := l.buckets[key]
+	if ok {
+		l.recent.MoveToFront(e)
+	} else {
+		if len(l.buckets) >= l.maxClients {
+			l.remove(l.recent.Back())
+		}
+		e = l.recent.PushFront(&bucket{key: key, tokens: l.burst, updated: now})
+		l.buckets[key] = e
+	}
+	b := e.Value.(*bucket)
+	b.tokens = l.refill(b, now)
+	b.updated = now
+
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	}
+	b.tokens--
+	return true, 0
+}
+
+// refill returns the tokens in b at now
+func (l *RateLimiter) refill(b *bucket, now time.Time) float64 {
+	return math.Min(l.burst, b.tokens+now.Sub(b.updated).Seconds()*l.rate)
+}
+
+// sweep drops the buckets that have refilled completely, as a client without
+// a bucket starts with a full one anyway. It runs at most once per sweepInterval.
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for e := l.recent.Front(); e != nil; {
+		next := e.Next()
+		if l.refill(e.Value.(*bucket), now) >= l.burst {
+			l.remove(e)
+		}
+		e = next
+	}
+}
+
+// remove forgets the client of the bucket in e
+func (l *RateLimiter) remove(e *list.Element) {
+	delete(l.buckets, l.recent.Remove(e).(*bucket).key)
+}
+
+// RateLimit refuses requests with 429 once the client IP has used up its
+// tokens in limiter. It is meant for the report submission routes. The client
+// IP honours the router's trusted proxies.
+func RateLimit(limiter *RateLimiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ok, wait := limiter.Allow(c.ClientIP()); !ok {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+			return
+		}
+		c.Next()
+	}
+}
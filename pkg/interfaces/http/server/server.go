@@ -0,0 +1,122 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+// Closer is a resource released once the server has drained, such as the repository
+type Closer interface {
+	Close(ctx context.Context) error
+}
+
+// Closers closes several resources in order, sharing the deadline
+type Closers []Closer
+
+// Close implements Closer, closing every resource even when one fails
+func (c Closers) Close(ctx context.Context) error {
+	var errs []error
+	for _, closer := range c {
+		errs = append(errs, closer.Close(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// Server is an HTTP server that drains in-flight requests on shutdown and then
+// closes its resources
+type Server struct {
+	httpServer   *http.Server
+	closer       Closer
+	drainTimeout time.Duration
+	ingestPaths  []string
+	draining     atomic.Bool
+}
+
+// New creates a server for the handler listening on addr. On shutdown it refuses
+// new POST requests to ingestPaths and waits up to drainTimeout for in-flight
+// requests before closing closer.
+func New(addr string, handler http.Handler, closer Closer, drainTimeout time.Duration, ingestPaths []string) *Server {
+	s := &Server{
+		closer:       closer,
+		drainTimeout: drainTimeout,
+		ingestPaths:  ingestPaths,
+	}
+	s.httpServer = &http.Server{
+		Addr:              addr,
+		Handler:           s.rejectIngestWhileDraining(handler),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return s
+}
+
+// Draining reports whether the server is shutting down
+func (s *Server) Draining() bool {
+	return s.draining.Load()
+}
+
+// Run listens on the server's address and serves until ctx is cancelled
+func (s *Server) Run(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return errors.Join(err, s.close())
+	}
+	return s.Serve(ctx, listener)
+}
+
+// Serve accepts connections on listener until ctx is cancelled. It then stops
+// accepting new ingest, waits for in-flight requests to complete within the
+// drain timeout and finally closes the server's resources.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.httpServer.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return errors.Join(err, s.close())
+	case <-ctx.Done():
+	}
+
+	s.draining.Store(true)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.drainTimeout)
+	defer cancel()
+
+	shutdownErr := s.httpServer.Shutdown(shutdownCtx)
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		shutdownErr = errors.Join(shutdownErr, err)
+	}
+
+	return errors.Join(shutdownErr, s.close())
+}
+
+// close releases the server's resources
+func (s *Server) close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.drainTimeout)
+	defer cancel()
+
+	return s.closer.Close(ctx)
+}
+
+// rejectIngestWhileDraining answers new ingest requests with 503 once shutdown
+// has started, so clients retry against another instance. Reads and other writes,
+// such as issue triage, are still served.
+func (s *Server) rejectIngestWhileDraining(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.Draining() && r.Method == http.MethodPost && slices.Contains(s.ingestPaths, r.URL.Path) {
+			w.Header().Set("Connection", "close")
+			w.Header().Set("Retry-After", "5")
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"error":"server is shutting down"}`))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
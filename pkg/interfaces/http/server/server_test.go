@@ -0,0 +1,152 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingCloser records when it is closed
+type recordingCloser struct {
+	closed   atomic.Int32
+	closedAt atomic.Int64
+}
+
+func (c *recordingCloser) Close(ctx context.Context) error {
+	c.closed.Add(1)
+	c.closedAt.Store(time.Now().UnixNano())
+	return nil
+}
+
+func TestServeDrainsInFlightRequestsBeforeClosing(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var finishedAt atomic.Int64
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/reports" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		close(started)
+		<-release
+		finishedAt.Store(time.Now().UnixNano())
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	closer := &recordingCloser{}
+	srv := New("", handler, closer, 5*time.Second, []string{"/api/v1/reports"})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- srv.Serve(ctx, listener)
+	}()
+
+	responses := make(chan int, 1)
+	go func() {
+		resp, err := http.Post("http://"+listener.Addr().String()+"/api/v1/reports", "application/json", nil)
+		if err != nil {
+			responses <- 0
+			return
+		}
+		resp.Body.Close()
+		responses <- resp.StatusCode
+	}()
+
+	<-started
+	cancel()
+
+	// New ingest is refused while the in-flight request is still running
+	assert.Eventually(t, srv.Draining, time.Second, 10*time.Millisecond)
+	w := httptest.NewRecorder()
+	srv.httpServer.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/reports", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	w = httptest.NewRecorder()
+	srv.httpServer.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/issues", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Writes other than ingest, such as issue triage, are still served
+	w = httptest.NewRecorder()
+	srv.httpServer.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/issues/abc/status", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	assert.Equal(t, int32(0), closer.closed.Load(), "repository closed before requests drained")
+
+	close(release)
+
+	assert.Equal(t, http.StatusCreated, <-responses)
+	assert.NoError(t, <-served)
+	assert.Equal(t, int32(1), closer.closed.Load())
+	assert.GreaterOrEqual(t, closer.closedAt.Load(), finishedAt.Load())
+}
+
+func TestServeClosesAfterDrainTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})
+
+	closer := &recordingCloser{}
+	srv := New("", handler, closer, 50*time.Millisecond, nil)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- srv.Serve(ctx, listener)
+	}()
+
+	go func() {
+		resp, err := http.Get("http://" + listener.Addr().String() + "/stuck")
+		if err == nil {
+			resp.Body.Close()
+		}
+	}()
+
+	<-started
+	cancel()
+
+	select {
+	case err := <-served:
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not give up draining after the timeout")
+	}
+	assert.Equal(t, int32(1), closer.closed.Load())
+}
+
+// failingCloser fails to close
+type failingCloser struct{}
+
+func (failingCloser) Close(ctx context.Context) error {
+	return assert.AnError
+}
+
+func TestClosers(t *testing.T) {
+	first, last := &recordingCloser{}, &recordingCloser{}
+
+	err := Closers{first, failingCloser{}, last}.Close(context.Background())
+	assert.ErrorIs(t, err, assert.AnError)
+	// A failure doesn't keep the later resources open
+	assert.Equal(t, int32(1), first.closed.Load())
+	assert.Equal(t, int32(1), last.closed.Load())
+	assert.LessOrEqual(t, first.closedAt.Load(), last.closedAt.Load())
+}